@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"os"
@@ -8,6 +9,8 @@ import (
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/joho/godotenv"
+	"github.com/mtbuzato/go-challenge/internal/blob"
+	"github.com/mtbuzato/go-challenge/internal/model"
 	"github.com/mtbuzato/go-challenge/internal/repository"
 )
 
@@ -18,10 +21,11 @@ func main() {
 	}
 
 	cfg := mysql.Config{
-		User:   os.Getenv("MYSQL_USER"),
-		Passwd: os.Getenv("MYSQL_PASSWORD"),
-		Addr:   os.Getenv("MYSQL_HOST") + ":" + os.Getenv("MYSQL_PORT"),
-		DBName: os.Getenv("MYSQL_DATABASE"),
+		User:      os.Getenv("MYSQL_USER"),
+		Passwd:    os.Getenv("MYSQL_PASSWORD"),
+		Addr:      os.Getenv("MYSQL_HOST") + ":" + os.Getenv("MYSQL_PORT"),
+		DBName:    os.Getenv("MYSQL_DATABASE"),
+		ParseTime: true,
 	}
 
 	db, err := sql.Open("mysql", cfg.FormatDSN())
@@ -31,18 +35,36 @@ func main() {
 
 	defer db.Close()
 
-	repo := repository.NewTaskRepository(db)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(context.Background(), db, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
 
-	testVanilla(repo)
+		return
+	}
+
+	attachmentsDir := os.Getenv("ATTACHMENTS_DIR")
+	if attachmentsDir == "" {
+		attachmentsDir = "attachments"
+	}
+
+	blobs, err := blob.NewLocalStore(attachmentsDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	repo := repository.NewTaskRepository(db, blobs)
+
+	testVanilla(context.Background(), repo)
 }
 
-func testVanilla(repo *repository.TaskRepository) {
-	task, err := repo.Create("Test 1")
+func testVanilla(ctx context.Context, repo *repository.TaskRepository) {
+	task, err := repo.Create(ctx, model.Task{Name: "Test 1"}, "cli")
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	tasks, err := repo.ListAll()
+	tasks, err := repo.ListAll(ctx)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -51,7 +73,7 @@ func testVanilla(repo *repository.TaskRepository) {
 		log.Fatal("Expected task to have been created.")
 	}
 
-	gottenTask, err := repo.GetByID(task.ID)
+	gottenTask, err := repo.GetByID(ctx, task.ID)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -60,7 +82,7 @@ func testVanilla(repo *repository.TaskRepository) {
 		log.Fatal("Expected task to have been returned.")
 	}
 
-	tasks, err = repo.ListByCompletion(true)
+	tasks, err = repo.ListByCompletion(ctx, true)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -70,12 +92,17 @@ func testVanilla(repo *repository.TaskRepository) {
 	}
 
 	task.Completed = true
-	err = repo.Update(task)
+	err = repo.Update(ctx, task, "cli")
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	tasks, err = repo.ListByCompletion(true)
+	task, err = repo.GetByID(ctx, task.ID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	tasks, err = repo.ListByCompletion(ctx, true)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -83,4 +110,14 @@ func testVanilla(repo *repository.TaskRepository) {
 	if len(tasks) != 1 || !reflect.DeepEqual(task, tasks[0]) {
 		log.Fatal("Expected task to have been found.")
 	}
+
+	err = repo.Delete(ctx, task.ID, "cli")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = repo.GetByID(ctx, task.ID)
+	if err == nil {
+		log.Fatal("Expected task to have been deleted.")
+	}
 }
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/mtbuzato/go-challenge/internal/migrations"
+)
+
+const migrateUsage = "Usage: cli migrate status|up|down|to VERSION"
+
+// Runs the migrate subcommand with the given arguments, which follow the
+// subcommand name.
+func runMigrate(ctx context.Context, db *sql.DB, args []string) error {
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	switch {
+	case args[0] == "status" && len(args) == 1:
+		return printStatus(ctx, migrator)
+	case args[0] == "up" && len(args) == 1:
+		err = migrator.Up(ctx)
+	case args[0] == "down" && len(args) == 1:
+		err = migrator.Down(ctx)
+	case args[0] == "to" && len(args) == 2:
+		version, parseErr := strconv.ParseInt(args[1], 10, 64)
+		if parseErr != nil || version < 0 {
+			return fmt.Errorf("Invalid migration version: %s.", args[1])
+		}
+
+		err = migrator.To(ctx, version)
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return printStatus(ctx, migrator)
+}
+
+func printStatus(ctx context.Context, migrator *migrations.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		applied := "pending"
+		if status.AppliedAt != nil {
+			applied = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+
+		fmt.Printf("%04d %-32s %s\n", status.Version, status.Name, applied)
+	}
+
+	return nil
+}
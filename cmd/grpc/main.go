@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"net"
@@ -10,6 +11,9 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/mtbuzato/go-challenge/internal/api"
 	"github.com/mtbuzato/go-challenge/internal/apigrpc"
+	"github.com/mtbuzato/go-challenge/internal/blob"
+	"github.com/mtbuzato/go-challenge/internal/migrations"
+	"github.com/mtbuzato/go-challenge/internal/model"
 	"github.com/mtbuzato/go-challenge/internal/orm"
 	"github.com/mtbuzato/go-challenge/internal/repository"
 	"google.golang.org/grpc"
@@ -22,10 +26,11 @@ func main() {
 	}
 
 	cfg := mysql.Config{
-		User:   os.Getenv("MYSQL_USER"),
-		Passwd: os.Getenv("MYSQL_PASSWORD"),
-		Addr:   os.Getenv("MYSQL_HOST") + ":" + os.Getenv("MYSQL_PORT"),
-		DBName: os.Getenv("MYSQL_DATABASE"),
+		User:      os.Getenv("MYSQL_USER"),
+		Passwd:    os.Getenv("MYSQL_PASSWORD"),
+		Addr:      os.Getenv("MYSQL_HOST") + ":" + os.Getenv("MYSQL_PORT"),
+		DBName:    os.Getenv("MYSQL_DATABASE"),
+		ParseTime: true,
 	}
 
 	db, err := sql.Open("mysql", cfg.FormatDSN())
@@ -35,14 +40,44 @@ func main() {
 
 	defer db.Close()
 
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := migrator.Check(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+
+	if data := os.Getenv("TASK_WORKFLOW"); data != "" {
+		workflow, err := model.ParseWorkflow(data)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err := model.SetWorkflow(workflow); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	attachmentsDir := os.Getenv("ATTACHMENTS_DIR")
+	if attachmentsDir == "" {
+		attachmentsDir = "attachments"
+	}
+
+	blobs, err := blob.NewLocalStore(attachmentsDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	var repo api.TaskRepository
 	if os.Getenv("DB_IMPL") == "orm" {
-		repo, err = orm.NewTaskRepository(db)
+		repo, err = orm.NewTaskRepository(db, blobs)
 		if err != nil {
 			log.Fatal(err)
 		}
 	} else {
-		repo = repository.NewTaskRepository(db)
+		repo = repository.NewTaskRepository(db, blobs)
 	}
 
 	listen, err := net.Listen("tcp", ":8080")
@@ -52,6 +87,9 @@ func main() {
 
 	grpcServer := grpc.NewServer()
 	apigrpc.RegisterTaskServiceServer(grpcServer, apigrpc.NewGRPCServer(repo))
+	apigrpc.RegisterProjectServiceServer(grpcServer, apigrpc.NewProjectServer(repo))
+	apigrpc.RegisterCommentServiceServer(grpcServer, apigrpc.NewCommentServer(repo))
+	apigrpc.RegisterAttachmentServiceServer(grpcServer, apigrpc.NewAttachmentServer(repo))
 
 	if err := grpcServer.Serve(listen); err != nil {
 		log.Fatal(err)
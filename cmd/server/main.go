@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"net/http"
@@ -9,10 +10,18 @@ import (
 	"github.com/go-sql-driver/mysql"
 	"github.com/joho/godotenv"
 	"github.com/mtbuzato/go-challenge/internal/api"
+	"github.com/mtbuzato/go-challenge/internal/blob"
+	"github.com/mtbuzato/go-challenge/internal/migrations"
+	"github.com/mtbuzato/go-challenge/internal/model"
 	"github.com/mtbuzato/go-challenge/internal/orm"
 	"github.com/mtbuzato/go-challenge/internal/repository"
 )
 
+type taskRepository interface {
+	api.TaskRepository
+	taskPurger
+}
+
 func main() {
 	err := godotenv.Load()
 	if err != nil {
@@ -20,10 +29,11 @@ func main() {
 	}
 
 	cfg := mysql.Config{
-		User:   os.Getenv("MYSQL_USER"),
-		Passwd: os.Getenv("MYSQL_PASSWORD"),
-		Addr:   os.Getenv("MYSQL_HOST") + ":" + os.Getenv("MYSQL_PORT"),
-		DBName: os.Getenv("MYSQL_DATABASE"),
+		User:      os.Getenv("MYSQL_USER"),
+		Passwd:    os.Getenv("MYSQL_PASSWORD"),
+		Addr:      os.Getenv("MYSQL_HOST") + ":" + os.Getenv("MYSQL_PORT"),
+		DBName:    os.Getenv("MYSQL_DATABASE"),
+		ParseTime: true,
 	}
 
 	db, err := sql.Open("mysql", cfg.FormatDSN())
@@ -33,16 +43,53 @@ func main() {
 
 	defer db.Close()
 
-	var repo api.TaskRepository
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := migrator.Check(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+
+	if data := os.Getenv("TASK_WORKFLOW"); data != "" {
+		workflow, err := model.ParseWorkflow(data)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err := model.SetWorkflow(workflow); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	attachmentsDir := os.Getenv("ATTACHMENTS_DIR")
+	if attachmentsDir == "" {
+		attachmentsDir = "attachments"
+	}
+
+	blobs, err := blob.NewLocalStore(attachmentsDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var repo taskRepository
 	if os.Getenv("DB_IMPL") == "orm" {
-		repo, err = orm.NewTaskRepository(db)
+		repo, err = orm.NewTaskRepository(db, blobs)
 		if err != nil {
 			log.Fatal(err)
 		}
 	} else {
-		repo = repository.NewTaskRepository(db)
+		repo = repository.NewTaskRepository(db, blobs)
 	}
 
+	go runPurger(
+		context.Background(),
+		repo,
+		durationFromEnv("TRASH_RETENTION", defaultTrashRetention),
+		durationFromEnv("TRASH_PURGE_INTERVAL", defaultPurgeInterval),
+	)
+
 	server := api.NewAPIServer(repo)
 	http.ListenAndServe(":8080", server)
 }
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+)
+
+const (
+	defaultTrashRetention = 30 * 24 * time.Hour
+	defaultPurgeInterval  = time.Hour
+)
+
+type taskPurger interface {
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Reads a duration from the given environment variable, falling back to the
+// default value when it is unset.
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Fatalf("Invalid %s: %q.", key, value)
+	}
+
+	return duration
+}
+
+// Periodically removes tasks that have been in the trash for longer than the
+// retention period.
+func runPurger(ctx context.Context, repo taskPurger, retention time.Duration, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := repo.Purge(ctx, time.Now().Add(-retention))
+		if err != nil {
+			log.Printf("Purger Error: %s\n", err.Error())
+		} else if purged > 0 {
+			log.Printf("Purged %d tasks from the trash.\n", purged)
+		}
+
+		<-ticker.C
+	}
+}
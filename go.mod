@@ -9,7 +9,10 @@ require (
 	github.com/golang/protobuf v1.5.2
 	github.com/joho/godotenv v1.4.0
 	github.com/lucsky/cuid v1.2.1
+	github.com/microcosm-cc/bluemonday v1.0.18
 	github.com/stretchr/testify v1.7.0
+	github.com/teambition/rrule-go v1.8.2
+	github.com/yuin/goldmark v1.4.12
 	golang.org/x/net v0.0.0-20220225172249-27dd8689420f // indirect
 	golang.org/x/sys v0.0.0-20220307203707-22a9840ba4d7 // indirect
 	google.golang.org/genproto v0.0.0-20220308174144-ae0e22291548 // indirect
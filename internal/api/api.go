@@ -1,21 +1,59 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mtbuzato/go-challenge/internal/errors"
 	"github.com/mtbuzato/go-challenge/internal/model"
 )
 
+// Every method takes the context of the request it serves, so that queries are
+// cancelled when the client goes away.
 type TaskRepository interface {
-	ListAll() ([]model.Task, error)
-	ListByCompletion(completed bool) ([]model.Task, error)
-	Create(name string) (model.Task, error)
-	GetByID(id string) (model.Task, error)
-	Update(task model.Task) error
+	ListAll(ctx context.Context) ([]model.Task, error)
+	ListByCompletion(ctx context.Context, completed bool) ([]model.Task, error)
+	Query(ctx context.Context, query model.TaskQuery) (model.TaskPage, error)
+	Search(ctx context.Context, query string, limit int) ([]model.Task, error)
+	Create(ctx context.Context, task model.Task, actor string) (model.Task, error)
+	GetByID(ctx context.Context, id string) (model.Task, error)
+	GetTree(ctx context.Context, id string) (model.TaskTree, error)
+	Update(ctx context.Context, task model.Task, actor string) error
+	Patch(ctx context.Context, id string, patch model.TaskPatch, actor string) error
+	Batch(ctx context.Context, operations []model.BatchOperation, atomic bool, actor string) (model.BatchResponse, error)
+	Delete(ctx context.Context, id string, actor string) error
+	ListTrash(ctx context.Context) ([]model.Task, error)
+	Restore(ctx context.Context, id string, actor string) error
+	ListTags(ctx context.Context) ([]model.Tag, error)
+	ListTaskTags(ctx context.Context, id string) ([]model.Tag, error)
+	AttachTag(ctx context.Context, id string, name string) (model.Tag, error)
+	DetachTag(ctx context.Context, id string, name string) error
+	ListProjects(ctx context.Context) ([]model.Project, error)
+	GetProject(ctx context.Context, id string) (model.Project, error)
+	CreateProject(ctx context.Context, project model.Project) (model.Project, error)
+	UpdateProject(ctx context.Context, project model.Project) error
+	DeleteProject(ctx context.Context, id string) error
+	MoveTask(ctx context.Context, id string, projectID *string, actor string) error
+	ReorderTask(ctx context.Context, id string, afterID *string, beforeID *string) error
+	ListDependencies(ctx context.Context, id string) (model.TaskDependencies, error)
+	AddDependency(ctx context.Context, id string, blockerID string) error
+	RemoveDependency(ctx context.Context, id string, blockerID string) error
+	ListComments(ctx context.Context, id string) ([]model.Comment, error)
+	CreateComment(ctx context.Context, comment model.Comment) (model.Comment, error)
+	DeleteComment(ctx context.Context, id string, commentID string) error
+	ListAttachments(ctx context.Context, id string) ([]model.Attachment, error)
+	OpenAttachment(ctx context.Context, id string, attachmentID string) (model.Attachment, io.ReadCloser, error)
+	CreateAttachment(ctx context.Context, attachment model.Attachment, content io.Reader) (model.Attachment, error)
+	DeleteAttachment(ctx context.Context, id string, attachmentID string) error
+	GetHistory(ctx context.Context, id string) ([]model.HistoryEntry, error)
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type apiServer struct {
@@ -33,6 +71,10 @@ func NewAPIServer(repo TaskRepository) *apiServer {
 
 	router.HandleFunc("/tasks", server.handleTasks)
 	router.HandleFunc("/tasks/", server.handleTask)
+	router.HandleFunc("/tasks:batch", server.handleBatch)
+	router.HandleFunc("/tags", server.handleTags)
+	router.HandleFunc("/projects", server.handleProjects)
+	router.HandleFunc("/projects/", server.handleProject)
 
 	router.HandleFunc("/", server.handleNotFound)
 
@@ -55,7 +97,10 @@ func (s *apiServer) handleError(w http.ResponseWriter, err error) {
 			}
 		}
 
-		fmt.Fprint(w, "{\"error\": \"", err.Error(), "\"}")
+		// Messages may include values sent by clients, so they are encoded rather
+		// than written as is.
+		str, _ := json.Marshal(map[string]string{"error": err.Error()})
+		w.Write(str)
 	} else {
 		w.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprint(w, "{\"error\": \"An unknown error ocurred.\"}")
@@ -63,11 +108,102 @@ func (s *apiServer) handleError(w http.ResponseWriter, err error) {
 	}
 }
 
+// Reports a request body that could not be decoded, keeping the message of
+// validation errors raised while decoding it.
+func (s *apiServer) handleBodyError(w http.ResponseWriter, err error) {
+	if errors.IsExternal(err) {
+		s.handleError(w, err)
+		return
+	}
+
+	s.handleError(w, errors.NewExternalError("Invalid body."))
+}
+
+// Returns who is making a request, as identified by the optional X-Actor
+// header. It is recorded in the history of the tasks the request changes.
+func actorOf(r *http.Request) string {
+	return r.Header.Get("X-Actor")
+}
+
+// Returns the entity tag of a task, which changes along with its version.
+func taskETag(task model.Task) string {
+	return `"` + strconv.FormatInt(task.Version, 10) + `"`
+}
+
+// Returns the task version required by the optional If-Match header, or zero
+// when any version is accepted.
+func ifMatchVersion(r *http.Request) (int64, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(value, `"`), 10, 64)
+	if err != nil || version <= 0 || value != taskETag(model.Task{Version: version}) {
+		return 0, errPreconditionFailed()
+	}
+
+	return version, nil
+}
+
+func errPreconditionFailed() error {
+	return errors.NewHTTPError("Task does not match the given ETag.", http.StatusPreconditionFailed)
+}
+
 func (s *apiServer) handleNotFound(w http.ResponseWriter, r *http.Request) {
 	s.handleError(w, errors.NewHTTPError("Endpoint not found.", http.StatusNotFound))
 }
 
+// Checks the ?render= parameter accepted by every task endpoint.
+func validateRender(r *http.Request) error {
+	switch r.URL.Query().Get("render") {
+	case "", "html":
+		return nil
+	default:
+		return errors.NewExternalError("Invalid render mode.")
+	}
+}
+
+// Fills in the HTML rendering of the task description when the request asks
+// for it with ?render=html.
+func renderTask(r *http.Request, task *model.Task) error {
+	if r.URL.Query().Get("render") != "html" {
+		return nil
+	}
+
+	return task.RenderDescription()
+}
+
+func renderTasks(r *http.Request, tasks []model.Task) error {
+	for i := range tasks {
+		if err := renderTask(r, &tasks[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func renderTaskTree(r *http.Request, tree *model.TaskTree) error {
+	if err := renderTask(r, &tree.Task); err != nil {
+		return err
+	}
+
+	for i := range tree.Subtasks {
+		if err := renderTaskTree(r, &tree.Subtasks[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (s *apiServer) handleTasks(w http.ResponseWriter, r *http.Request) {
+	if err := validateRender(r); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
 	switch r.Method {
 	case "GET":
 		s.getTasks(w, r)
@@ -79,8 +215,49 @@ func (s *apiServer) handleTasks(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *apiServer) handleTask(w http.ResponseWriter, r *http.Request) {
+	if err := validateRender(r); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
 	split := strings.Split(r.URL.Path, "/")
 
+	if len(split) == 3 {
+		switch split[2] {
+		case "trash":
+			s.handleTrash(w, r)
+			return
+		case "search":
+			s.handleSearch(w, r)
+			return
+		}
+	}
+
+	if len(split) == 4 {
+		s.handleTaskAction(w, r, split[2], split[3])
+		return
+	}
+
+	if len(split) == 5 && split[3] == "tags" {
+		s.handleTaskTag(w, r, split[2], split[4])
+		return
+	}
+
+	if len(split) == 5 && split[3] == "dependencies" {
+		s.handleTaskDependency(w, r, split[2], split[4])
+		return
+	}
+
+	if len(split) == 5 && split[3] == "attachments" {
+		s.handleTaskAttachment(w, r, split[2], split[4])
+		return
+	}
+
+	if len(split) == 5 && split[3] == "comments" {
+		s.handleTaskComment(w, r, split[2], split[4])
+		return
+	}
+
 	if len(split) != 3 {
 		s.handleNotFound(w, r)
 		return
@@ -93,21 +270,83 @@ func (s *apiServer) handleTask(w http.ResponseWriter, r *http.Request) {
 		s.getTask(w, r, taskId)
 	case "PUT":
 		s.putTask(w, r, taskId)
+	case "PATCH":
+		s.patchTask(w, r, taskId)
+	case "DELETE":
+		s.deleteTask(w, r, taskId)
+	default:
+		s.handleNotFound(w, r)
+	}
+}
+
+func (s *apiServer) handleTaskAction(w http.ResponseWriter, r *http.Request, taskId string, action string) {
+	switch {
+	case action == "restore" && r.Method == "POST":
+		s.restoreTask(w, r, taskId)
+	case action == "move" && r.Method == "POST":
+		s.moveTask(w, r, taskId)
+	case action == "tree" && r.Method == "GET":
+		s.getTaskTree(w, r, taskId)
+	case action == "tags" && r.Method == "GET":
+		s.getTaskTags(w, r, taskId)
+	case action == "tags" && r.Method == "POST":
+		s.postTaskTag(w, r, taskId)
+	case action == "dependencies" && r.Method == "GET":
+		s.getTaskDependencies(w, r, taskId)
+	case action == "dependencies" && r.Method == "POST":
+		s.postTaskDependency(w, r, taskId)
+	case action == "comments" && r.Method == "GET":
+		s.getTaskComments(w, r, taskId)
+	case action == "comments" && r.Method == "POST":
+		s.postTaskComment(w, r, taskId)
+	case action == "attachments" && r.Method == "GET":
+		s.getTaskAttachments(w, r, taskId)
+	case action == "attachments" && r.Method == "POST":
+		s.postTaskAttachment(w, r, taskId)
+	case action == "history" && r.Method == "GET":
+		s.getTaskHistory(w, r, taskId)
+	default:
+		s.handleNotFound(w, r)
+	}
+}
+
+func (s *apiServer) handleTrash(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		s.getTrash(w, r)
+	default:
+		s.handleNotFound(w, r)
+	}
+}
+
+func (s *apiServer) handleSearch(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		s.searchTasks(w, r)
 	default:
 		s.handleNotFound(w, r)
 	}
 }
 
 func (s *apiServer) getTasks(w http.ResponseWriter, r *http.Request) {
-	completed := r.URL.Query().Get("completed")
+	query := r.URL.Query()
+
+	for _, param := range taskQueryParams {
+		if query.Get(param) != "" {
+			s.queryTasks(w, r)
+			return
+		}
+	}
+
+	completed := query.Get("completed")
 
 	var tasks []model.Task
 	var err error
 
 	if completed == "" {
-		tasks, err = s.repo.ListAll()
+		tasks, err = s.repo.ListAll(r.Context())
 	} else {
-		tasks, err = s.repo.ListByCompletion(completed == "true")
+		tasks, err = s.repo.ListByCompletion(r.Context(), completed == "true")
 	}
 
 	if err != nil {
@@ -115,6 +354,181 @@ func (s *apiServer) getTasks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := renderTasks(r, tasks); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	str, err := json.Marshal(tasks)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(str)
+}
+
+// Query string parameters that switch task listings to paginated queries.
+var taskQueryParams = []string{
+	"name", "status", "project", "sort", "order", "limit", "cursor",
+	"created_after", "created_before", "updated_after", "updated_before",
+	"overdue", "due_within", "tag", "tag_mode", "blocked",
+}
+
+func parseTime(values url.Values, key string) (*time.Time, error) {
+	value := values.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.NewExternalError("Invalid " + key + ".")
+	}
+
+	return &t, nil
+}
+
+func parseTaskQuery(values url.Values) (model.TaskQuery, error) {
+	var err error
+
+	query := model.TaskQuery{
+		Name:      values.Get("name"),
+		Status:    model.Status(values.Get("status")),
+		ProjectID: values.Get("project"),
+		Sort:      values.Get("sort"),
+		Page:      model.PageRequest{Cursor: values.Get("cursor")},
+	}
+
+	if values.Get("completed") != "" {
+		completed, err := strconv.ParseBool(values.Get("completed"))
+		if err != nil {
+			return query, errors.NewExternalError("Invalid completion status.")
+		}
+
+		query.Completed = &completed
+	}
+
+	if values.Get("blocked") != "" {
+		blocked, err := strconv.ParseBool(values.Get("blocked"))
+		if err != nil {
+			return query, errors.NewExternalError("Invalid blocked filter.")
+		}
+
+		query.Blocked = &blocked
+	}
+
+	if values.Get("overdue") != "" {
+		query.Overdue, err = strconv.ParseBool(values.Get("overdue"))
+		if err != nil {
+			return query, errors.NewExternalError("Invalid overdue filter.")
+		}
+	}
+
+	if values.Get("due_within") != "" {
+		query.DueWithinDays, err = strconv.Atoi(values.Get("due_within"))
+		if err != nil {
+			return query, errors.NewExternalError("Invalid due date range.")
+		}
+	}
+
+	query.Tags = values["tag"]
+
+	switch values.Get("tag_mode") {
+	case "", "any":
+	case "all":
+		query.MatchAllTags = true
+	default:
+		return query, errors.NewExternalError("Invalid tag mode.")
+	}
+
+	switch values.Get("order") {
+	case "", "asc":
+	case "desc":
+		query.Descending = true
+	default:
+		return query, errors.NewExternalError("Invalid sort order.")
+	}
+
+	if values.Get("limit") != "" {
+		query.Page.Limit, err = strconv.Atoi(values.Get("limit"))
+		if err != nil {
+			return query, errors.NewExternalError("Invalid page limit.")
+		}
+	}
+
+	if query.CreatedAfter, err = parseTime(values, "created_after"); err != nil {
+		return query, err
+	}
+
+	if query.CreatedBefore, err = parseTime(values, "created_before"); err != nil {
+		return query, err
+	}
+
+	if query.UpdatedAfter, err = parseTime(values, "updated_after"); err != nil {
+		return query, err
+	}
+
+	if query.UpdatedBefore, err = parseTime(values, "updated_before"); err != nil {
+		return query, err
+	}
+
+	return query, nil
+}
+
+func (s *apiServer) queryTasks(w http.ResponseWriter, r *http.Request) {
+	query, err := parseTaskQuery(r.URL.Query())
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	page, err := s.repo.Query(r.Context(), query)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	if err := renderTasks(r, page.Tasks); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	str, err := json.Marshal(page)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(str)
+}
+
+func (s *apiServer) searchTasks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := 0
+	if query.Get("limit") != "" {
+		var err error
+		limit, err = strconv.Atoi(query.Get("limit"))
+		if err != nil {
+			s.handleError(w, errors.NewExternalError("Invalid search limit."))
+			return
+		}
+	}
+
+	tasks, err := s.repo.Search(r.Context(), query.Get("q"), limit)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	if err := renderTasks(r, tasks); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
 	str, err := json.Marshal(tasks)
 	if err != nil {
 		s.handleError(w, err)
@@ -126,41 +540,108 @@ func (s *apiServer) getTasks(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *apiServer) getTask(w http.ResponseWriter, r *http.Request, id string) {
-	task, err := s.repo.GetByID(id)
+	task, err := s.repo.GetByID(r.Context(), id)
 	if err != nil {
 		s.handleError(w, err)
 		return
 	}
 
+	if err := renderTask(r, &task); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
 	str, err := json.Marshal(task)
 	if err != nil {
 		s.handleError(w, err)
 		return
 	}
 
+	w.Header().Set("ETag", taskETag(task))
+	w.WriteHeader(http.StatusOK)
+	w.Write(str)
+}
+
+func (s *apiServer) getTaskTree(w http.ResponseWriter, r *http.Request, id string) {
+	tree, err := s.repo.GetTree(r.Context(), id)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	if err := renderTaskTree(r, &tree); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	str, err := json.Marshal(tree)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
 	w.Write(str)
 }
 
 type PostTaskBody struct {
-	Name string `json:"name"`
+	Name         string         `json:"name"`
+	Description  string         `json:"description"`
+	Status       model.Status   `json:"status"`
+	Priority     model.Priority `json:"priority"`
+	ProjectID    *string        `json:"project_id"`
+	ParentID     *string        `json:"parent_id"`
+	AutoComplete bool           `json:"auto_complete"`
+	Recurrence   string         `json:"recurrence"`
+	DueAt        *time.Time     `json:"due_at"`
 }
 
 func (s *apiServer) postTask(w http.ResponseWriter, r *http.Request) {
+	s.createTask(w, r, nil)
+}
+
+// Creates a task from the request body. When a project ID is given the task
+// is created in that project regardless of the body.
+func (s *apiServer) createTask(w http.ResponseWriter, r *http.Request, projectID *string) {
 	var taskBody PostTaskBody
 
 	err := json.NewDecoder(r.Body).Decode(&taskBody)
 	if err != nil {
-		s.handleError(w, errors.NewExternalError("Invalid body."))
+		s.handleBodyError(w, err)
 		return
 	}
 
-	task, err := s.repo.Create(taskBody.Name)
+	if projectID != nil {
+		taskBody.ProjectID = projectID
+	}
+
+	task := model.Task{
+		Name:         taskBody.Name,
+		Description:  taskBody.Description,
+		Status:       taskBody.Status,
+		Priority:     taskBody.Priority,
+		ProjectID:    taskBody.ProjectID,
+		ParentID:     taskBody.ParentID,
+		AutoComplete: taskBody.AutoComplete,
+		Recurrence:   taskBody.Recurrence,
+		DueAt:        taskBody.DueAt,
+	}
+
+	err = s.repo.Transaction(r.Context(), func(ctx context.Context) error {
+		var err error
+		task, err = s.repo.Create(ctx, task, actorOf(r))
+		return err
+	})
 	if err != nil {
 		s.handleError(w, err)
 		return
 	}
 
+	if err := renderTask(r, &task); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
 	str, err := json.Marshal(task)
 	if err != nil {
 		s.handleError(w, err)
@@ -172,8 +653,15 @@ func (s *apiServer) postTask(w http.ResponseWriter, r *http.Request) {
 }
 
 type PutTaskBody struct {
-	Name      string `json:"name"`
-	Completed bool   `json:"completed"`
+	Name         string         `json:"name"`
+	Description  string         `json:"description"`
+	Completed    *bool          `json:"completed"`
+	Status       model.Status   `json:"status"`
+	Priority     model.Priority `json:"priority"`
+	ParentID     *string        `json:"parent_id"`
+	AutoComplete bool           `json:"auto_complete"`
+	Recurrence   string         `json:"recurrence"`
+	DueAt        *time.Time     `json:"due_at"`
 }
 
 func (s *apiServer) putTask(w http.ResponseWriter, r *http.Request, id string) {
@@ -181,18 +669,210 @@ func (s *apiServer) putTask(w http.ResponseWriter, r *http.Request, id string) {
 
 	err := json.NewDecoder(r.Body).Decode(&taskBody)
 	if err != nil {
-		s.handleError(w, errors.NewExternalError("Invalid body."))
+		s.handleBodyError(w, err)
 		return
 	}
 
 	task := model.Task{
-		ID:        id,
-		Name:      taskBody.Name,
-		Completed: taskBody.Completed,
+		ID:           id,
+		Name:         taskBody.Name,
+		Description:  taskBody.Description,
+		Completed:    taskBody.Status == model.StatusDone,
+		Status:       taskBody.Status,
+		Priority:     taskBody.Priority,
+		ParentID:     taskBody.ParentID,
+		AutoComplete: taskBody.AutoComplete,
+		Recurrence:   taskBody.Recurrence,
+		DueAt:        taskBody.DueAt,
+	}
+
+	// Without a completion flag the status alone says whether the task is
+	// completed.
+	if taskBody.Completed != nil {
+		task.Completed = *taskBody.Completed
+	}
+
+	task.Version, err = ifMatchVersion(r)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	err = s.repo.Transaction(r.Context(), func(ctx context.Context) error {
+		return s.repo.Update(ctx, task, actorOf(r))
+	})
+	if err != nil {
+		if errors.IsConflict(err) && task.Version != 0 {
+			err = errPreconditionFailed()
+		}
+
+		s.handleError(w, err)
+		return
 	}
 
-	err = s.repo.Update(task)
+	s.writeUpdatedTask(w, r, id)
+}
+
+// Partially updates a task from a JSON Merge Patch, leaving the fields missing
+// from it untouched.
+func (s *apiServer) patchTask(w http.ResponseWriter, r *http.Request, id string) {
+	body, err := io.ReadAll(r.Body)
 	if err != nil {
+		s.handleBodyError(w, err)
+		return
+	}
+
+	patch, err := model.ParseMergePatch(body)
+	if err != nil {
+		s.handleBodyError(w, err)
+		return
+	}
+
+	patch.Task.Version, err = ifMatchVersion(r)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	err = s.repo.Transaction(r.Context(), func(ctx context.Context) error {
+		return s.repo.Patch(ctx, id, patch, actorOf(r))
+	})
+	if err != nil {
+		if errors.IsConflict(err) && patch.Task.Version != 0 {
+			err = errPreconditionFailed()
+		}
+
+		s.handleError(w, err)
+		return
+	}
+
+	s.writeUpdatedTask(w, r, id)
+}
+
+// Responds with the task with the given ID after it has been updated.
+func (s *apiServer) writeUpdatedTask(w http.ResponseWriter, r *http.Request, id string) {
+	task, err := s.repo.GetByID(r.Context(), id)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	if err := renderTask(r, &task); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	str, err := json.Marshal(task)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(task))
+	w.WriteHeader(http.StatusOK)
+	w.Write(str)
+}
+
+func (s *apiServer) deleteTask(w http.ResponseWriter, r *http.Request, id string) {
+	err := s.repo.Transaction(r.Context(), func(ctx context.Context) error {
+		return s.repo.Delete(ctx, id, actorOf(r))
+	})
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *apiServer) getTrash(w http.ResponseWriter, r *http.Request) {
+	tasks, err := s.repo.ListTrash(r.Context())
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	if err := renderTasks(r, tasks); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	str, err := json.Marshal(tasks)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(str)
+}
+
+func (s *apiServer) restoreTask(w http.ResponseWriter, r *http.Request, id string) {
+	err := s.repo.Transaction(r.Context(), func(ctx context.Context) error {
+		return s.repo.Restore(ctx, id, actorOf(r))
+	})
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	task, err := s.repo.GetByID(r.Context(), id)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	if err := renderTask(r, &task); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	str, err := json.Marshal(task)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(str)
+}
+
+// Moves a task next to the given neighbors in the manual order when any is
+// given, and to the given project otherwise.
+type MoveTaskBody struct {
+	ProjectID *string `json:"project_id"`
+	AfterID   *string `json:"after_id"`
+	BeforeID  *string `json:"before_id"`
+}
+
+func (s *apiServer) moveTask(w http.ResponseWriter, r *http.Request, id string) {
+	var moveBody MoveTaskBody
+
+	err := json.NewDecoder(r.Body).Decode(&moveBody)
+	if err != nil {
+		s.handleBodyError(w, err)
+		return
+	}
+
+	err = s.repo.Transaction(r.Context(), func(ctx context.Context) error {
+		if moveBody.AfterID != nil || moveBody.BeforeID != nil {
+			return s.repo.ReorderTask(ctx, id, moveBody.AfterID, moveBody.BeforeID)
+		}
+
+		return s.repo.MoveTask(ctx, id, moveBody.ProjectID, actorOf(r))
+	})
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	task, err := s.repo.GetByID(r.Context(), id)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	if err := renderTask(r, &task); err != nil {
 		s.handleError(w, err)
 		return
 	}
@@ -1,13 +1,17 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/mtbuzato/go-challenge/internal/errors"
 	"github.com/mtbuzato/go-challenge/internal/model"
@@ -17,17 +21,89 @@ import (
 type StubTaskRepository struct {
 	tasks        []model.Task
 	createdTasks []model.Task
+	trash        []model.Task
+	tags         map[string][]string
+	projects     []model.Project
+	blockers     map[string][]string
+	comments     []model.Comment
+	attachments  []model.Attachment
+	blobs        map[string][]byte
+	history      []model.HistoryEntry
+	transactions int
 }
 
-func (r *StubTaskRepository) ListAll() ([]model.Task, error) {
+func (r *StubTaskRepository) ListAll(ctx context.Context) ([]model.Task, error) {
 	return r.tasks, nil
 }
 
-func (r *StubTaskRepository) ListByCompletion(completed bool) ([]model.Task, error) {
+func (r *StubTaskRepository) ListByCompletion(ctx context.Context, completed bool) ([]model.Task, error) {
 	return r.tasks, nil
 }
 
-func (r *StubTaskRepository) GetByID(id string) (model.Task, error) {
+func (r *StubTaskRepository) Query(ctx context.Context, query model.TaskQuery) (model.TaskPage, error) {
+	if err := query.Validate(); err != nil {
+		return model.TaskPage{}, err
+	}
+
+	after := ""
+	if cursor := query.After(); cursor != nil {
+		after = cursor.ID()
+	}
+
+	tasks := []model.Task{}
+	for _, t := range r.tasks {
+		if t.ID > after &&
+			strings.Contains(t.Name, query.Name) &&
+			(query.Completed == nil || t.Completed == *query.Completed) &&
+			(query.Status == "" || t.Status == query.Status) &&
+			(query.ProjectID == "" || (t.ProjectID != nil && *t.ProjectID == query.ProjectID)) &&
+			r.hasTags(t.ID, query) &&
+			(query.Blocked == nil || r.isBlocked(t.ID) == *query.Blocked) {
+			tasks = append(tasks, t)
+		}
+	}
+
+	return model.NewTaskPage(tasks, query), nil
+}
+
+func (r *StubTaskRepository) hasTags(id string, query model.TaskQuery) bool {
+	names := query.TagNames()
+	if len(names) == 0 {
+		return true
+	}
+
+	matched := 0
+	for _, name := range names {
+		for _, tag := range r.tags[id] {
+			if tag == name {
+				matched++
+			}
+		}
+	}
+
+	if query.MatchAllTags {
+		return matched == len(names)
+	}
+
+	return matched > 0
+}
+
+func (r *StubTaskRepository) Search(ctx context.Context, query string, limit int) ([]model.Task, error) {
+	if err := model.ValidateSearch(query, limit); err != nil {
+		return nil, err
+	}
+
+	tasks := []model.Task{}
+	for _, t := range r.tasks {
+		if strings.Contains(strings.ToLower(t.Name), strings.ToLower(query)) {
+			tasks = append(tasks, t)
+		}
+	}
+
+	return tasks, nil
+}
+
+func (r *StubTaskRepository) GetByID(ctx context.Context, id string) (model.Task, error) {
 	var task model.Task
 	for _, t := range r.tasks {
 		if t.ID == id {
@@ -43,18 +119,44 @@ func (r *StubTaskRepository) GetByID(id string) (model.Task, error) {
 	return task, nil
 }
 
-func (r *StubTaskRepository) Create(name string) (model.Task, error) {
-	task := model.Task{ID: "4", Name: name, Completed: false}
+func (r *StubTaskRepository) GetTree(ctx context.Context, id string) (model.TaskTree, error) {
+	root, err := r.GetByID(ctx, id)
+	if err != nil {
+		return model.TaskTree{}, err
+	}
+
+	return model.NewTaskTree(root, r.tasks), nil
+}
+
+func (r *StubTaskRepository) Create(ctx context.Context, task model.Task, actor string) (model.Task, error) {
+	if task.ProjectID != nil {
+		if _, err := r.GetProject(ctx, *task.ProjectID); err != nil {
+			return model.Task{}, err
+		}
+	}
+
+	task.ID = "4"
 	r.createdTasks = append(r.createdTasks, task)
+	r.history = append(r.history, model.NewHistoryEntry(task.ID, model.HistoryCreated, actor, model.DiffTasks(model.Task{}, task)))
 	return task, nil
 }
 
-func (r *StubTaskRepository) Update(task model.Task) error {
+func (r *StubTaskRepository) Update(ctx context.Context, task model.Task, actor string) error {
 	found := false
 
 	for i, t := range r.tasks {
 		if t.ID == task.ID {
+			if task.Completed && !t.Completed && r.isBlocked(task.ID) {
+				return errors.NewExternalError("Task is blocked by open tasks.")
+			}
+
+			if task.Version != 0 && task.Version != t.Version {
+				return errors.NewConflictError("Task has been changed since it was read.")
+			}
+
+			task.Version = t.Version + 1
 			r.tasks[i] = task
+			r.history = append(r.history, model.NewHistoryEntry(task.ID, model.HistoryUpdated, actor, model.DiffTasks(t, task)))
 			found = true
 			break
 		}
@@ -63,15 +165,674 @@ func (r *StubTaskRepository) Update(task model.Task) error {
 	if !found {
 		return errors.NewHTTPError("Task not found.", http.StatusNotFound)
 	}
-
-	return nil
+
+	return nil
+}
+
+func (r *StubTaskRepository) Patch(ctx context.Context, id string, patch model.TaskPatch, actor string) error {
+	if err := patch.Validate(); err != nil {
+		return err
+	}
+
+	current, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return r.Update(ctx, patch.Apply(current), actor)
+}
+
+func (r *StubTaskRepository) Batch(ctx context.Context, operations []model.BatchOperation, atomic bool, actor string) (model.BatchResponse, error) {
+	if err := model.ValidateBatch(operations); err != nil {
+		return model.BatchResponse{}, err
+	}
+
+	tasks := append([]model.Task{}, r.tasks...)
+	createdTasks := append([]model.Task{}, r.createdTasks...)
+	trash := append([]model.Task{}, r.trash...)
+	history := append([]model.HistoryEntry{}, r.history...)
+
+	results := make([]model.BatchResult, len(operations))
+	for i, operation := range operations {
+		var err error
+
+		switch operation.Action {
+		case model.BatchCreate:
+			var task model.Task
+			task, err = r.Create(ctx, operation.Task, actor)
+			results[i].ID = task.ID
+		case model.BatchUpdate:
+			err = r.Patch(ctx, operation.ID, operation.Patch, actor)
+			results[i].ID = operation.ID
+		case model.BatchDelete:
+			err = r.Delete(ctx, operation.ID, actor)
+			results[i].ID = operation.ID
+		}
+
+		if err != nil {
+			results[i].Error = err.Error()
+		}
+	}
+
+	response := model.NewBatchResponse(results, atomic)
+	if !response.Applied {
+		r.tasks, r.createdTasks, r.trash, r.history = tasks, createdTasks, trash, history
+		return model.BatchResponse{}, &model.BatchNotAppliedError{Response: response}
+	}
+
+	return response, nil
+}
+
+func (r *StubTaskRepository) Delete(ctx context.Context, id string, actor string) error {
+	for i, t := range r.tasks {
+		if t.ID == id {
+			r.tasks = append(r.tasks[:i], r.tasks[i+1:]...)
+			r.trash = append(r.trash, t)
+			r.history = append(r.history, model.NewHistoryEntry(id, model.HistoryDeleted, actor, nil))
+			return nil
+		}
+	}
+
+	return errors.NewExternalError("Task not found.")
+}
+
+func (r *StubTaskRepository) ListTrash(ctx context.Context) ([]model.Task, error) {
+	return r.trash, nil
+}
+
+func (r *StubTaskRepository) Restore(ctx context.Context, id string, actor string) error {
+	for i, t := range r.trash {
+		if t.ID == id {
+			r.trash = append(r.trash[:i], r.trash[i+1:]...)
+			r.tasks = append(r.tasks, t)
+			r.history = append(r.history, model.NewHistoryEntry(id, model.HistoryRestored, actor, nil))
+			return nil
+		}
+	}
+
+	return errors.NewExternalError("Task not found in trash.")
+}
+
+func (r *StubTaskRepository) ListTags(ctx context.Context) ([]model.Tag, error) {
+	tags := []model.Tag{}
+	seen := map[string]bool{}
+
+	for _, task := range r.tasks {
+		for _, name := range r.tags[task.ID] {
+			if !seen[name] {
+				seen[name] = true
+				tags = append(tags, model.Tag{ID: name, Name: name})
+			}
+		}
+	}
+
+	return tags, nil
+}
+
+func (r *StubTaskRepository) ListTaskTags(ctx context.Context, id string) ([]model.Tag, error) {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	tags := []model.Tag{}
+	for _, name := range r.tags[id] {
+		tags = append(tags, model.Tag{ID: name, Name: name})
+	}
+
+	return tags, nil
+}
+
+func (r *StubTaskRepository) AttachTag(ctx context.Context, id string, name string) (model.Tag, error) {
+	tag, err := model.NewTag(name)
+	if err != nil {
+		return model.Tag{}, err
+	}
+
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return model.Tag{}, err
+	}
+
+	r.tags[id] = append(r.tags[id], tag.Name)
+	return model.Tag{ID: tag.Name, Name: tag.Name}, nil
+}
+
+func (r *StubTaskRepository) DetachTag(ctx context.Context, id string, name string) error {
+	for i, tag := range r.tags[id] {
+		if tag == model.NormalizeTagName(name) {
+			r.tags[id] = append(r.tags[id][:i], r.tags[id][i+1:]...)
+			return nil
+		}
+	}
+
+	return errors.NewExternalError("Tag not found on task.")
+}
+
+func (r *StubTaskRepository) ListProjects(ctx context.Context) ([]model.Project, error) {
+	return r.projects, nil
+}
+
+func (r *StubTaskRepository) GetProject(ctx context.Context, id string) (model.Project, error) {
+	for _, p := range r.projects {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+
+	return model.Project{}, errors.NewExternalError("Project not found.")
+}
+
+func (r *StubTaskRepository) CreateProject(ctx context.Context, project model.Project) (model.Project, error) {
+	if err := model.ValidateProjectName(project.Name); err != nil {
+		return model.Project{}, err
+	}
+
+	project.ID = "3"
+	r.projects = append(r.projects, project)
+	return project, nil
+}
+
+func (r *StubTaskRepository) UpdateProject(ctx context.Context, project model.Project) error {
+	if err := model.ValidateProjectName(project.Name); err != nil {
+		return err
+	}
+
+	for i, p := range r.projects {
+		if p.ID == project.ID {
+			r.projects[i] = project
+			return nil
+		}
+	}
+
+	return errors.NewExternalError("Project not found.")
+}
+
+func (r *StubTaskRepository) DeleteProject(ctx context.Context, id string) error {
+	for i, p := range r.projects {
+		if p.ID == id {
+			r.projects = append(r.projects[:i], r.projects[i+1:]...)
+			return nil
+		}
+	}
+
+	return errors.NewExternalError("Project not found.")
+}
+
+func (r *StubTaskRepository) MoveTask(ctx context.Context, id string, projectID *string, actor string) error {
+	if projectID != nil {
+		if _, err := r.GetProject(ctx, *projectID); err != nil {
+			return err
+		}
+	}
+
+	for i, t := range r.tasks {
+		if t.ID == id {
+			r.tasks[i].ProjectID = projectID
+			return nil
+		}
+	}
+
+	return errors.NewExternalError("Task not found.")
+}
+
+func (r *StubTaskRepository) ReorderTask(ctx context.Context, id string, afterID *string, beforeID *string) error {
+	if err := model.ValidateNeighbors(id, afterID, beforeID); err != nil {
+		return err
+	}
+
+	var lower, upper string
+	for _, n := range []struct {
+		id       *string
+		position *string
+	}{{afterID, &lower}, {beforeID, &upper}} {
+		if n.id == nil {
+			continue
+		}
+
+		neighbor, err := r.GetByID(ctx, *n.id)
+		if err != nil {
+			return errors.NewExternalError("Neighbor task not found.")
+		}
+		*n.position = neighbor.Position
+	}
+
+	position, err := model.PositionBetween(lower, upper)
+	if err != nil {
+		return err
+	}
+
+	for i, t := range r.tasks {
+		if t.ID == id {
+			r.tasks[i].Position = position
+			return nil
+		}
+	}
+
+	return errors.NewExternalError("Task not found.")
+}
+
+func (r *StubTaskRepository) ListDependencies(ctx context.Context, id string) (model.TaskDependencies, error) {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return model.TaskDependencies{}, err
+	}
+
+	dependencies := model.TaskDependencies{BlockedBy: []model.Task{}, Blocks: []model.Task{}}
+	for _, t := range r.tasks {
+		for _, blockerID := range r.blockers[id] {
+			if t.ID == blockerID {
+				dependencies.BlockedBy = append(dependencies.BlockedBy, t)
+			}
+		}
+
+		for _, blockerID := range r.blockers[t.ID] {
+			if blockerID == id {
+				dependencies.Blocks = append(dependencies.Blocks, t)
+			}
+		}
+	}
+
+	return dependencies, nil
+}
+
+func (r *StubTaskRepository) AddDependency(ctx context.Context, id string, blockerID string) error {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	if _, err := r.GetByID(ctx, blockerID); err != nil {
+		return errors.NewExternalError("Blocker task not found.")
+	}
+
+	if err := model.ValidateDependency(id, blockerID, r.blockers[blockerID]); err != nil {
+		return err
+	}
+
+	if r.blockers == nil {
+		r.blockers = map[string][]string{}
+	}
+
+	r.blockers[id] = append(r.blockers[id], blockerID)
+	return nil
+}
+
+func (r *StubTaskRepository) RemoveDependency(ctx context.Context, id string, blockerID string) error {
+	for i, b := range r.blockers[id] {
+		if b == blockerID {
+			r.blockers[id] = append(r.blockers[id][:i], r.blockers[id][i+1:]...)
+			return nil
+		}
+	}
+
+	return errors.NewExternalError("Dependency not found.")
+}
+
+func (r *StubTaskRepository) ListComments(ctx context.Context, id string) ([]model.Comment, error) {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	comments := []model.Comment{}
+	for _, c := range r.comments {
+		if c.TaskID == id {
+			comments = append(comments, c)
+		}
+	}
+
+	return comments, nil
+}
+
+func (r *StubTaskRepository) CreateComment(ctx context.Context, comment model.Comment) (model.Comment, error) {
+	comment.Init()
+
+	if err := comment.Validate(); err != nil {
+		return model.Comment{}, err
+	}
+
+	if _, err := r.GetByID(ctx, comment.TaskID); err != nil {
+		return model.Comment{}, err
+	}
+
+	r.comments = append(r.comments, comment)
+
+	return comment, nil
+}
+
+func (r *StubTaskRepository) DeleteComment(ctx context.Context, id string, commentID string) error {
+	for i, c := range r.comments {
+		if c.ID == commentID && c.TaskID == id {
+			r.comments = append(r.comments[:i], r.comments[i+1:]...)
+			return nil
+		}
+	}
+
+	return errors.NewExternalError("Comment not found.")
+}
+
+func (r *StubTaskRepository) ListAttachments(ctx context.Context, id string) ([]model.Attachment, error) {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	attachments := []model.Attachment{}
+	for _, a := range r.attachments {
+		if a.TaskID == id {
+			attachments = append(attachments, a)
+		}
+	}
+
+	return attachments, nil
+}
+
+func (r *StubTaskRepository) OpenAttachment(ctx context.Context, id string, attachmentID string) (model.Attachment, io.ReadCloser, error) {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return model.Attachment{}, nil, err
+	}
+
+	for _, a := range r.attachments {
+		if a.ID == attachmentID && a.TaskID == id {
+			return a, io.NopCloser(bytes.NewReader(r.blobs[a.ID])), nil
+		}
+	}
+
+	return model.Attachment{}, nil, errors.NewExternalError("Attachment not found.")
+}
+
+func (r *StubTaskRepository) CreateAttachment(ctx context.Context, attachment model.Attachment, content io.Reader) (model.Attachment, error) {
+	attachment.Init()
+
+	if err := attachment.Validate(); err != nil {
+		return model.Attachment{}, err
+	}
+
+	if _, err := r.GetByID(ctx, attachment.TaskID); err != nil {
+		return model.Attachment{}, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(content, model.MaxAttachmentSize+1))
+	if err != nil {
+		return model.Attachment{}, err
+	}
+
+	attachment.Size = int64(len(data))
+
+	if err := model.ValidateAttachmentSize(attachment.Size); err != nil {
+		return model.Attachment{}, err
+	}
+
+	if r.blobs == nil {
+		r.blobs = map[string][]byte{}
+	}
+
+	r.blobs[attachment.ID] = data
+	r.attachments = append(r.attachments, attachment)
+
+	return attachment, nil
+}
+
+func (r *StubTaskRepository) DeleteAttachment(ctx context.Context, id string, attachmentID string) error {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	for i, a := range r.attachments {
+		if a.ID == attachmentID && a.TaskID == id {
+			r.attachments = append(r.attachments[:i], r.attachments[i+1:]...)
+			delete(r.blobs, a.ID)
+			return nil
+		}
+	}
+
+	return errors.NewExternalError("Attachment not found.")
+}
+
+func (r *StubTaskRepository) GetHistory(ctx context.Context, id string) ([]model.HistoryEntry, error) {
+	found := false
+	for _, t := range append(r.tasks, r.trash...) {
+		found = found || t.ID == id
+	}
+
+	if !found {
+		return nil, errors.NewExternalError("Task not found.")
+	}
+
+	history := []model.HistoryEntry{}
+	for _, entry := range r.history {
+		if entry.TaskID == id {
+			history = append(history, entry)
+		}
+	}
+
+	return history, nil
+}
+
+func (r *StubTaskRepository) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	r.transactions++
+	return fn(ctx)
+}
+
+func (r *StubTaskRepository) isBlocked(id string) bool {
+	for _, blockerID := range r.blockers[id] {
+		for _, t := range r.tasks {
+			if t.ID == blockerID && !t.Completed {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func TestGETTasks(t *testing.T) {
+	tasks := []model.Task{
+		{ID: "1", Name: "Task 1", Completed: false},
+		{ID: "2", Name: "Task 2", Completed: true},
+		{ID: "3", Name: "Task 3", Completed: false},
+	}
+
+	server := NewAPIServer(&StubTaskRepository{tasks: tasks})
+
+	tests := map[string]struct {
+		query          string
+		expectedStatus int
+		expectedTasks  []model.Task
+	}{
+		"List all tasks": {
+			query:          "",
+			expectedStatus: http.StatusOK,
+			expectedTasks:  tasks,
+		},
+		"List completed tasks": {
+			query:          "?completed=true",
+			expectedStatus: http.StatusOK,
+			expectedTasks:  []model.Task{tasks[1]},
+		},
+		"List incomplete tasks": {
+			query:          "?completed=false",
+			expectedStatus: http.StatusOK,
+			expectedTasks:  []model.Task{tasks[0], tasks[2]},
+		},
+		"List all tasks with invalid query": {
+			query:          "?completed=invalid",
+			expectedStatus: http.StatusOK,
+			expectedTasks:  tasks,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			req, err := http.NewRequest("GET", "/tasks"+test.query, nil)
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestGETTasksQuery(t *testing.T) {
+	tasks := []model.Task{
+		{ID: "cl09rb83d000009l13y5n5ur1", Name: "Task 1", Completed: false, Status: model.StatusInProgress},
+		{ID: "cl09rb83d000009l13y5n5ur2", Name: "Task 2", Completed: true, Status: model.StatusDone},
+		{ID: "cl09rb83d000009l13y5n5ur3", Name: "Task 3", Completed: false, Status: model.StatusTodo},
+	}
+
+	server := NewAPIServer(&StubTaskRepository{
+		tasks: tasks,
+		tags: map[string][]string{
+			tasks[0].ID: {"backend", "urgent"},
+			tasks[1].ID: {"backend"},
+		},
+		blockers: map[string][]string{
+			tasks[1].ID: {tasks[2].ID},
+			tasks[2].ID: {tasks[0].ID},
+		},
+	})
+
+	tests := map[string]struct {
+		query          string
+		expectedStatus int
+		expectedPage   model.TaskPage
+	}{
+		"First page": {
+			query:          "?limit=2",
+			expectedStatus: http.StatusOK,
+			expectedPage: model.TaskPage{
+				Tasks:      tasks[:2],
+				NextCursor: model.EncodeCursor(tasks[1], "id", false),
+			},
+		},
+		"Last page": {
+			query:          "?limit=2&cursor=" + model.EncodeCursor(tasks[1], "id", false),
+			expectedStatus: http.StatusOK,
+			expectedPage: model.TaskPage{
+				Tasks: tasks[2:],
+			},
+		},
+		"First page of incomplete tasks": {
+			query:          "?limit=1&completed=false",
+			expectedStatus: http.StatusOK,
+			expectedPage: model.TaskPage{
+				Tasks:      tasks[:1],
+				NextCursor: model.EncodeCursor(tasks[0], "id", false),
+			},
+		},
+		"Tasks filtered by name": {
+			query:          "?name=2",
+			expectedStatus: http.StatusOK,
+			expectedPage: model.TaskPage{
+				Tasks: tasks[1:2],
+			},
+		},
+		"Tasks with any of the tags": {
+			query:          "?tag=urgent&tag=backend",
+			expectedStatus: http.StatusOK,
+			expectedPage: model.TaskPage{
+				Tasks: tasks[:2],
+			},
+		},
+		"Tasks with all of the tags": {
+			query:          "?tag=urgent&tag=backend&tag_mode=all",
+			expectedStatus: http.StatusOK,
+			expectedPage: model.TaskPage{
+				Tasks: tasks[:1],
+			},
+		},
+		"Tasks in progress": {
+			query:          "?status=in_progress",
+			expectedStatus: http.StatusOK,
+			expectedPage: model.TaskPage{
+				Tasks: tasks[:1],
+			},
+		},
+		"Invalid status": {
+			query:          "?status=blocked",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Blocked tasks": {
+			query:          "?blocked=true",
+			expectedStatus: http.StatusOK,
+			expectedPage: model.TaskPage{
+				Tasks: tasks[1:],
+			},
+		},
+		"Unblocked tasks": {
+			query:          "?blocked=false",
+			expectedStatus: http.StatusOK,
+			expectedPage: model.TaskPage{
+				Tasks: tasks[:1],
+			},
+		},
+		"Invalid blocked filter": {
+			query:          "?blocked=maybe",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Invalid tag mode": {
+			query:          "?tag=urgent&tag_mode=some",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Invalid tag": {
+			query:          "?tag=needs+review",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Invalid sort field": {
+			query:          "?sort=invalid",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Invalid sort order": {
+			query:          "?sort=name&order=invalid",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Invalid overdue filter": {
+			query:          "?overdue=maybe",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Invalid due date range": {
+			query:          "?due_within=-1",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Invalid creation time": {
+			query:          "?created_after=yesterday",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Invalid limit": {
+			query:          "?limit=abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Invalid cursor": {
+			query:          "?cursor=abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			req, err := http.NewRequest("GET", "/tasks"+test.query, nil)
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+
+			if test.expectedStatus == http.StatusOK {
+				var page model.TaskPage
+				err = json.Unmarshal(w.Body.Bytes(), &page)
+				assert.NoError(err)
+				assert.Equal(test.expectedPage, page)
+			}
+		})
+	}
 }
 
-func TestGETTasks(t *testing.T) {
+func TestGETSearchTasks(t *testing.T) {
 	tasks := []model.Task{
-		{ID: "1", Name: "Task 1", Completed: false},
-		{ID: "2", Name: "Task 2", Completed: true},
-		{ID: "3", Name: "Task 3", Completed: false},
+		{ID: "1", Name: "Buy milk", Completed: false},
+		{ID: "2", Name: "Walk the dog", Completed: true},
 	}
 
 	server := NewAPIServer(&StubTaskRepository{tasks: tasks})
@@ -81,32 +842,30 @@ func TestGETTasks(t *testing.T) {
 		expectedStatus int
 		expectedTasks  []model.Task
 	}{
-		"List all tasks": {
-			query:          "",
+		"Search tasks": {
+			query:          "?q=milk",
 			expectedStatus: http.StatusOK,
-			expectedTasks:  tasks,
+			expectedTasks:  tasks[:1],
 		},
-		"List completed tasks": {
-			query:          "?completed=true",
+		"Search without results": {
+			query:          "?q=cat",
 			expectedStatus: http.StatusOK,
-			expectedTasks:  []model.Task{tasks[1]},
+			expectedTasks:  []model.Task{},
 		},
-		"List incomplete tasks": {
-			query:          "?completed=false",
-			expectedStatus: http.StatusOK,
-			expectedTasks:  []model.Task{tasks[0], tasks[2]},
+		"Search without query": {
+			query:          "",
+			expectedStatus: http.StatusBadRequest,
 		},
-		"List all tasks with invalid query": {
-			query:          "?completed=invalid",
-			expectedStatus: http.StatusOK,
-			expectedTasks:  tasks,
+		"Search with invalid limit": {
+			query:          "?q=milk&limit=abc",
+			expectedStatus: http.StatusBadRequest,
 		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			assert := assert.New(t)
-			req, err := http.NewRequest("GET", "/tasks"+test.query, nil)
+			req, err := http.NewRequest("GET", "/tasks/search"+test.query, nil)
 			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
 			assert.NoError(err)
 
@@ -114,15 +873,22 @@ func TestGETTasks(t *testing.T) {
 			server.ServeHTTP(w, req)
 
 			assert.Equal(test.expectedStatus, w.Code)
+
+			if test.expectedStatus == http.StatusOK {
+				var tasks []model.Task
+				err = json.Unmarshal(w.Body.Bytes(), &tasks)
+				assert.NoError(err)
+				assert.Equal(test.expectedTasks, tasks)
+			}
 		})
 	}
 }
 
 func TestGETTask(t *testing.T) {
 	tasks := []model.Task{
-		{ID: "1", Name: "Task 1", Completed: false},
-		{ID: "2", Name: "Task 2", Completed: true},
-		{ID: "3", Name: "Task 3", Completed: false},
+		{ID: "1", Name: "Task 1", Description: "Buy **milk**", Completed: false, Version: 3},
+		{ID: "2", Name: "Task 2", Completed: true, Version: 1},
+		{ID: "3", Name: "Task 3", Completed: false, Version: 1},
 	}
 
 	server := NewAPIServer(&StubTaskRepository{tasks: tasks})
@@ -141,6 +907,21 @@ func TestGETTask(t *testing.T) {
 			id:             "4",
 			expectedStatus: http.StatusNotFound,
 		},
+		"Get a task with its description rendered": {
+			id:             "1?render=html",
+			expectedStatus: http.StatusOK,
+			expectedTask: model.Task{
+				ID:              "1",
+				Name:            "Task 1",
+				Description:     "Buy **milk**",
+				DescriptionHTML: "<p>Buy <strong>milk</strong></p>\n",
+				Version:         3,
+			},
+		},
+		"Get a task with an invalid render mode": {
+			id:             "1?render=pdf",
+			expectedStatus: http.StatusBadRequest,
+		},
 	}
 
 	for name, test := range tests {
@@ -160,12 +941,14 @@ func TestGETTask(t *testing.T) {
 				err = json.Unmarshal(w.Body.Bytes(), &task)
 				assert.NoError(err)
 				assert.Equal(test.expectedTask, task)
+				assert.Equal(`"3"`, w.Header().Get("ETag"))
 			}
 		})
 	}
 }
 
 func TestPOSTTask(t *testing.T) {
+	dueAt := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
 	server := NewAPIServer(&StubTaskRepository{})
 
 	tests := map[string]struct {
@@ -178,6 +961,35 @@ func TestPOSTTask(t *testing.T) {
 			expectedStatus: http.StatusCreated,
 			expectedTask:   model.Task{ID: "4", Name: "Task 4", Completed: false},
 		},
+		"Create a task with a due date": {
+			body:           `{"name":"Task 4","due_at":"2022-03-01T12:00:00Z"}`,
+			expectedStatus: http.StatusCreated,
+			expectedTask:   model.Task{ID: "4", Name: "Task 4", Completed: false, DueAt: &dueAt},
+		},
+		"Create a task with a priority": {
+			body:           `{"name":"Task 4","priority":"high"}`,
+			expectedStatus: http.StatusCreated,
+			expectedTask:   model.Task{ID: "4", Name: "Task 4", Completed: false, Priority: model.PriorityHigh},
+		},
+		"Create a task with an invalid priority": {
+			body:           `{"name":"Task 4","priority":"whenever"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Create a task with a description": {
+			body:           `{"name":"Task 4","description":"Buy **milk**"}`,
+			expectedStatus: http.StatusCreated,
+			expectedTask:   model.Task{ID: "4", Name: "Task 4", Description: "Buy **milk**"},
+		},
+		"Create a task in progress": {
+			body:           `{"name":"Task 4","status":"in_progress"}`,
+			expectedStatus: http.StatusCreated,
+			expectedTask:   model.Task{ID: "4", Name: "Task 4", Status: model.StatusInProgress},
+		},
+		"Create a recurring task": {
+			body:           `{"name":"Task 4","recurrence":"FREQ=WEEKLY;BYDAY=MO"}`,
+			expectedStatus: http.StatusCreated,
+			expectedTask:   model.Task{ID: "4", Name: "Task 4", Recurrence: "FREQ=WEEKLY;BYDAY=MO"},
+		},
 		"Create a task with invalid JSON": {
 			body:           `{"name":"Task 4`,
 			expectedStatus: http.StatusBadRequest,
@@ -215,7 +1027,10 @@ func TestPUTTask(t *testing.T) {
 		{ID: "3", Name: "Task 3", Completed: false},
 	}
 
-	server := NewAPIServer(&StubTaskRepository{tasks: tasks})
+	server := NewAPIServer(&StubTaskRepository{
+		tasks:    tasks,
+		blockers: map[string][]string{"3": {"1"}},
+	})
 
 	tests := map[string]struct {
 		id             string
@@ -227,7 +1042,7 @@ func TestPUTTask(t *testing.T) {
 			id:             "1",
 			body:           `{"name":"Task 1 Updated"}`,
 			expectedStatus: http.StatusOK,
-			expectedTask:   model.Task{ID: "1", Name: "Task 1 Updated", Completed: false},
+			expectedTask:   model.Task{ID: "1", Name: "Task 1 Updated", Completed: false, Version: 1},
 		},
 		"Update a task that does not exist": {
 			id:             "4",
@@ -239,6 +1054,17 @@ func TestPUTTask(t *testing.T) {
 			body:           `{"name":"Task 1 Updated`,
 			expectedStatus: http.StatusBadRequest,
 		},
+		"Complete a task through its status": {
+			id:             "2",
+			body:           `{"name":"Task 2","status":"done"}`,
+			expectedStatus: http.StatusOK,
+			expectedTask:   model.Task{ID: "2", Name: "Task 2", Completed: true, Status: model.StatusDone, Version: 1},
+		},
+		"Complete a blocked task": {
+			id:             "3",
+			body:           `{"name":"Task 3","completed":true}`,
+			expectedStatus: http.StatusBadRequest,
+		},
 	}
 
 	for name, test := range tests {
@@ -262,3 +1088,320 @@ func TestPUTTask(t *testing.T) {
 		})
 	}
 }
+
+func TestPUTTaskIfMatch(t *testing.T) {
+	tests := map[string]struct {
+		ifMatch        string
+		expectedStatus int
+		expectedETag   string
+	}{
+		"Update a task with its current ETag": {
+			ifMatch:        `"2"`,
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"3"`,
+		},
+		"Update a task with any ETag": {
+			ifMatch:        "*",
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"3"`,
+		},
+		"Update a task with an outdated ETag": {
+			ifMatch:        `"1"`,
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		"Update a task with an invalid ETag": {
+			ifMatch:        "2",
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			server := NewAPIServer(&StubTaskRepository{
+				tasks: []model.Task{{ID: "1", Name: "Task 1", Version: 2}},
+			})
+
+			req, err := http.NewRequest("PUT", "/tasks/1", strings.NewReader(`{"name":"Task 1 Updated"}`))
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			req.Header.Set("If-Match", test.ifMatch)
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+			assert.Equal(test.expectedETag, w.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestPATCHTask(t *testing.T) {
+	tests := map[string]struct {
+		id             string
+		body           string
+		ifMatch        string
+		expectedStatus int
+		expectedTask   model.Task
+		expectedError  string
+	}{
+		"Rename a completed task": {
+			id:             "1",
+			body:           `{"name":"Task 1 Updated"}`,
+			expectedStatus: http.StatusOK,
+			expectedTask:   model.Task{ID: "1", Name: "Task 1 Updated", Completed: true, Status: model.StatusDone, Priority: model.PriorityHigh, Version: 3},
+		},
+		"Reset the priority of a task": {
+			id:             "1",
+			body:           `{"priority":null}`,
+			expectedStatus: http.StatusOK,
+			expectedTask:   model.Task{ID: "1", Name: "Task 1", Completed: true, Status: model.StatusDone, Version: 3},
+		},
+		"Patch a task with its current ETag": {
+			id:             "1",
+			body:           `{"description":"Buy milk"}`,
+			ifMatch:        `"2"`,
+			expectedStatus: http.StatusOK,
+			expectedTask:   model.Task{ID: "1", Name: "Task 1", Description: "Buy milk", Completed: true, Status: model.StatusDone, Priority: model.PriorityHigh, Version: 3},
+		},
+		"Patch a task with an outdated ETag": {
+			id:             "1",
+			body:           `{"name":"Task 1 Updated"}`,
+			ifMatch:        `"1"`,
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		"Patch a field that cannot be patched": {
+			id:             "1",
+			body:           `{"project_id":"cl09rb83d000009l13y5n5ur8"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Patch a field with a quote in its name": {
+			id:             "1",
+			body:           `{"x\", \"admin\": \"y":1}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  `Invalid patch field: "x\", \"admin\": \"y".`,
+		},
+		"Patch a task with invalid JSON": {
+			id:             "1",
+			body:           `{"name":"Task 1 Updated`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Patch a task that does not exist": {
+			id:             "2",
+			body:           `{"name":"Task 2 Updated"}`,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			repo := &StubTaskRepository{
+				tasks: []model.Task{
+					{ID: "1", Name: "Task 1", Completed: true, Status: model.StatusDone, Priority: model.PriorityHigh, Version: 2},
+				},
+			}
+			server := NewAPIServer(repo)
+
+			req, err := http.NewRequest("PATCH", "/tasks/"+test.id, strings.NewReader(test.body))
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			req.Header.Set("If-Match", test.ifMatch)
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+
+			if test.expectedStatus == http.StatusOK {
+				var task model.Task
+				err = json.Unmarshal(w.Body.Bytes(), &task)
+				assert.NoError(err)
+				assert.Equal(test.expectedTask, task)
+				assert.Equal(1, repo.transactions)
+			}
+
+			if test.expectedError != "" {
+				var response map[string]string
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(err)
+				assert.Equal(map[string]string{"error": test.expectedError}, response)
+			}
+		})
+	}
+}
+
+func TestDELETETask(t *testing.T) {
+	tasks := []model.Task{
+		{ID: "1", Name: "Task 1", Completed: false},
+		{ID: "2", Name: "Task 2", Completed: true},
+	}
+
+	repo := &StubTaskRepository{tasks: tasks}
+	server := NewAPIServer(repo)
+
+	tests := map[string]struct {
+		id             string
+		expectedStatus int
+	}{
+		"Delete a task": {
+			id:             "1",
+			expectedStatus: http.StatusNoContent,
+		},
+		"Delete a task that does not exist": {
+			id:             "4",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			req, err := http.NewRequest("DELETE", "/tasks/"+test.id, nil)
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+
+			if test.expectedStatus == http.StatusNoContent {
+				_, err := repo.GetByID(context.Background(), test.id)
+				assert.Error(err)
+			}
+		})
+	}
+}
+
+func TestGETTrash(t *testing.T) {
+	trash := []model.Task{
+		{ID: "1", Name: "Task 1", Completed: false},
+	}
+
+	server := NewAPIServer(&StubTaskRepository{trash: trash})
+
+	assert := assert.New(t)
+	req, err := http.NewRequest("GET", "/tasks/trash", nil)
+	req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+	assert.NoError(err)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(http.StatusOK, w.Code)
+
+	var tasks []model.Task
+	err = json.Unmarshal(w.Body.Bytes(), &tasks)
+	assert.NoError(err)
+	assert.Equal(trash, tasks)
+}
+
+func TestPOSTRestoreTask(t *testing.T) {
+	trash := []model.Task{
+		{ID: "1", Name: "Task 1", Completed: false},
+	}
+
+	server := NewAPIServer(&StubTaskRepository{trash: trash})
+
+	tests := map[string]struct {
+		id             string
+		expectedStatus int
+		expectedTask   model.Task
+	}{
+		"Restore a task": {
+			id:             "1",
+			expectedStatus: http.StatusOK,
+			expectedTask:   trash[0],
+		},
+		"Restore a task that is not in the trash": {
+			id:             "4",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			req, err := http.NewRequest("POST", "/tasks/"+test.id+"/restore", nil)
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+
+			if test.expectedStatus == http.StatusOK {
+				var task model.Task
+				err = json.Unmarshal(w.Body.Bytes(), &task)
+				assert.NoError(err)
+				assert.Equal(test.expectedTask, task)
+			}
+		})
+	}
+}
+
+func TestGETTaskTree(t *testing.T) {
+	rootID, childID := "1", "2"
+	tasks := []model.Task{
+		{ID: rootID, Name: "Task 1", AutoComplete: true},
+		{ID: childID, Name: "Task 2", ParentID: &rootID},
+		{ID: "3", Name: "Task 3", ParentID: &childID, Completed: true},
+		{ID: "4", Name: "Task 4"},
+	}
+
+	server := NewAPIServer(&StubTaskRepository{tasks: tasks})
+
+	tests := map[string]struct {
+		id             string
+		expectedStatus int
+		expectedTree   model.TaskTree
+	}{
+		"Get the tree of a task": {
+			id:             rootID,
+			expectedStatus: http.StatusOK,
+			expectedTree: model.TaskTree{
+				Task: tasks[0],
+				Subtasks: []model.TaskTree{
+					{
+						Task: tasks[1],
+						Subtasks: []model.TaskTree{
+							{Task: tasks[2], Subtasks: []model.TaskTree{}},
+						},
+					},
+				},
+			},
+		},
+		"Get the tree of a task without subtasks": {
+			id:             "4",
+			expectedStatus: http.StatusOK,
+			expectedTree:   model.TaskTree{Task: tasks[3], Subtasks: []model.TaskTree{}},
+		},
+		"Get the tree of a task that does not exist": {
+			id:             "5",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			req, err := http.NewRequest("GET", "/tasks/"+test.id+"/tree", nil)
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+
+			if test.expectedStatus == http.StatusOK {
+				var tree model.TaskTree
+				err = json.Unmarshal(w.Body.Bytes(), &tree)
+				assert.NoError(err)
+				assert.Equal(test.expectedTree, tree)
+			}
+		})
+	}
+}
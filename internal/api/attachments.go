@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+// Maximum size of an upload request, leaving room for the multipart headers
+// around the file.
+const maxUploadSize = model.MaxAttachmentSize + 1<<20
+
+func (s *apiServer) handleTaskAttachment(w http.ResponseWriter, r *http.Request, taskId string, attachmentId string) {
+	switch r.Method {
+	case "GET":
+		s.getTaskAttachment(w, r, taskId, attachmentId)
+	case "DELETE":
+		s.deleteTaskAttachment(w, r, taskId, attachmentId)
+	default:
+		s.handleNotFound(w, r)
+	}
+}
+
+func (s *apiServer) getTaskAttachments(w http.ResponseWriter, r *http.Request, id string) {
+	attachments, err := s.repo.ListAttachments(r.Context(), id)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	str, err := json.Marshal(attachments)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(str)
+}
+
+// Streams the content of an attachment as a download.
+func (s *apiServer) getTaskAttachment(w http.ResponseWriter, r *http.Request, id string, attachmentId string) {
+	attachment, content, err := s.repo.OpenAttachment(r.Context(), id, attachmentId)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, content)
+}
+
+// Attaches the file sent in the "file" field of a multipart form. The file is
+// streamed to the blob store instead of being buffered in memory.
+func (s *apiServer) postTaskAttachment(w http.ResponseWriter, r *http.Request, id string) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		s.handleError(w, errors.NewExternalError("Invalid multipart body."))
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			s.handleError(w, errors.NewExternalError("A file is required."))
+			return
+		}
+
+		if err != nil {
+			s.handleError(w, errors.NewExternalError("Invalid multipart body."))
+			return
+		}
+
+		if part.FormName() != "file" {
+			continue
+		}
+
+		attachment, err := s.repo.CreateAttachment(r.Context(), model.Attachment{
+			TaskID:      id,
+			Name:        part.FileName(),
+			ContentType: part.Header.Get("Content-Type"),
+		}, part)
+		if err != nil {
+			s.handleError(w, err)
+			return
+		}
+
+		str, err := json.Marshal(attachment)
+		if err != nil {
+			s.handleError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write(str)
+		return
+	}
+}
+
+func (s *apiServer) deleteTaskAttachment(w http.ResponseWriter, r *http.Request, id string, attachmentId string) {
+	err := s.repo.DeleteAttachment(r.Context(), id, attachmentId)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,273 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"testing"
+
+	"github.com/mtbuzato/go-challenge/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	attachedTaskID = "cl09rb83d000009l13y5n5ur1"
+	attachmentID   = "cl09rb83d000009l13y5n5at1"
+)
+
+func newAttachmentStubRepository() *StubTaskRepository {
+	return &StubTaskRepository{
+		tasks: []model.Task{
+			{ID: attachedTaskID, Name: "Task 1", Completed: false},
+			{ID: "cl09rb83d000009l13y5n5ur2", Name: "Task 2", Completed: false},
+		},
+		attachments: []model.Attachment{
+			{ID: attachmentID, TaskID: attachedTaskID, Name: "server.log", ContentType: "text/plain", Size: 5},
+		},
+		blobs: map[string][]byte{
+			attachmentID: []byte("hello"),
+		},
+	}
+}
+
+// Builds a multipart body with a single file field of the given name.
+func multipartFile(t *testing.T, field string, filename string, contentType string, content []byte) (*bytes.Buffer, string) {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="`+field+`"; filename="`+filename+`"`)
+	header.Set("Content-Type", contentType)
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := part.Write(content); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return body, writer.FormDataContentType()
+}
+
+func TestGETTaskAttachments(t *testing.T) {
+	repo := newAttachmentStubRepository()
+	server := NewAPIServer(repo)
+
+	tests := map[string]struct {
+		id                  string
+		expectedStatus      int
+		expectedAttachments []model.Attachment
+	}{
+		"Get the attachments of a task": {
+			id:                  attachedTaskID,
+			expectedStatus:      http.StatusOK,
+			expectedAttachments: repo.attachments,
+		},
+		"Get the attachments of a task without any": {
+			id:                  "cl09rb83d000009l13y5n5ur2",
+			expectedStatus:      http.StatusOK,
+			expectedAttachments: []model.Attachment{},
+		},
+		"Get the attachments of a task that does not exist": {
+			id:             "cl09rb83d000009l13y5n5ur3",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			req, err := http.NewRequest("GET", "/tasks/"+test.id+"/attachments", nil)
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+
+			if test.expectedStatus == http.StatusOK {
+				var attachments []model.Attachment
+				err = json.Unmarshal(w.Body.Bytes(), &attachments)
+				assert.NoError(err)
+				assert.Equal(test.expectedAttachments, attachments)
+			}
+		})
+	}
+}
+
+func TestGETTaskAttachment(t *testing.T) {
+	server := NewAPIServer(newAttachmentStubRepository())
+
+	tests := map[string]struct {
+		path           string
+		expectedStatus int
+	}{
+		"Download an attachment": {
+			path:           "/tasks/" + attachedTaskID + "/attachments/" + attachmentID,
+			expectedStatus: http.StatusOK,
+		},
+		"Download an attachment through another task": {
+			path:           "/tasks/cl09rb83d000009l13y5n5ur2/attachments/" + attachmentID,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			req, err := http.NewRequest("GET", test.path, nil)
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+
+			if test.expectedStatus == http.StatusOK {
+				assert.Equal("hello", w.Body.String())
+				assert.Equal("text/plain", w.Header().Get("Content-Type"))
+				assert.Equal("5", w.Header().Get("Content-Length"))
+				assert.Equal(`attachment; filename=server.log`, w.Header().Get("Content-Disposition"))
+			}
+		})
+	}
+}
+
+func TestPOSTTaskAttachment(t *testing.T) {
+	tests := map[string]struct {
+		id             string
+		field          string
+		filename       string
+		contentType    string
+		content        []byte
+		expectedStatus int
+	}{
+		"Attach a file": {
+			id:             attachedTaskID,
+			field:          "file",
+			filename:       "screenshot.png",
+			contentType:    "image/png",
+			content:        []byte("png"),
+			expectedStatus: http.StatusCreated,
+		},
+		"Attach a file of a type that is not allowed": {
+			id:             attachedTaskID,
+			field:          "file",
+			filename:       "script.sh",
+			contentType:    "application/x-sh",
+			content:        []byte("echo"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Attach a file that is too large": {
+			id:             attachedTaskID,
+			field:          "file",
+			filename:       "server.log",
+			contentType:    "text/plain",
+			content:        make([]byte, model.MaxAttachmentSize+1),
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Attach a file in the wrong field": {
+			id:             attachedTaskID,
+			field:          "upload",
+			filename:       "server.log",
+			contentType:    "text/plain",
+			content:        []byte("hello"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Attach a file to a task that does not exist": {
+			id:             "cl09rb83d000009l13y5n5ur3",
+			field:          "file",
+			filename:       "server.log",
+			contentType:    "text/plain",
+			content:        []byte("hello"),
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			repo := newAttachmentStubRepository()
+			server := NewAPIServer(repo)
+
+			body, contentType := multipartFile(t, test.field, test.filename, test.contentType, test.content)
+
+			req, err := http.NewRequest("POST", "/tasks/"+test.id+"/attachments", body)
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			req.Header.Set("Content-Type", contentType)
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+
+			if test.expectedStatus == http.StatusCreated {
+				var attachment model.Attachment
+				err = json.Unmarshal(w.Body.Bytes(), &attachment)
+				assert.NoError(err)
+				assert.Equal(test.filename, attachment.Name)
+				assert.Equal(int64(len(test.content)), attachment.Size)
+				assert.Equal(test.content, repo.blobs[attachment.ID])
+			}
+		})
+	}
+}
+
+func TestPOSTTaskAttachmentWithoutMultipart(t *testing.T) {
+	assert := assert.New(t)
+	server := NewAPIServer(newAttachmentStubRepository())
+
+	req, err := http.NewRequest("POST", "/tasks/"+attachedTaskID+"/attachments", bytes.NewBufferString(`{}`))
+	req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+	assert.NoError(err)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(http.StatusBadRequest, w.Code)
+}
+
+func TestDELETETaskAttachment(t *testing.T) {
+	tests := map[string]struct {
+		path           string
+		expectedStatus int
+	}{
+		"Delete an attachment": {
+			path:           "/tasks/" + attachedTaskID + "/attachments/" + attachmentID,
+			expectedStatus: http.StatusNoContent,
+		},
+		"Delete an attachment that does not exist": {
+			path:           "/tasks/" + attachedTaskID + "/attachments/cl09rb83d000009l13y5n5at2",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			server := NewAPIServer(newAttachmentStubRepository())
+
+			req, err := http.NewRequest("DELETE", test.path, nil)
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+type BatchOperationBody struct {
+	Action model.BatchAction `json:"action"`
+	ID     string            `json:"id"`
+	Task   PostTaskBody      `json:"task"`
+	// A JSON Merge Patch applied by updates.
+	Patch   json.RawMessage `json:"patch"`
+	Version int64           `json:"version"`
+}
+
+type BatchBody struct {
+	Atomic     bool                 `json:"atomic"`
+	Operations []BatchOperationBody `json:"operations"`
+}
+
+func (s *apiServer) handleBatch(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "POST":
+		s.postBatch(w, r)
+	default:
+		s.handleNotFound(w, r)
+	}
+}
+
+// Applies a batch of task operations, responding with the result of each one.
+// Failed operations do not stop the batch unless it is atomic, in which case
+// none of them are applied.
+func (s *apiServer) postBatch(w http.ResponseWriter, r *http.Request) {
+	var body BatchBody
+
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		s.handleBodyError(w, err)
+		return
+	}
+
+	operations := make([]model.BatchOperation, len(body.Operations))
+	for i, operationBody := range body.Operations {
+		operations[i] = model.BatchOperation{
+			Action: operationBody.Action,
+			ID:     operationBody.ID,
+			Task: model.Task{
+				Name:         operationBody.Task.Name,
+				Description:  operationBody.Task.Description,
+				Status:       operationBody.Task.Status,
+				Priority:     operationBody.Task.Priority,
+				ProjectID:    operationBody.Task.ProjectID,
+				ParentID:     operationBody.Task.ParentID,
+				AutoComplete: operationBody.Task.AutoComplete,
+				Recurrence:   operationBody.Task.Recurrence,
+				DueAt:        operationBody.Task.DueAt,
+			},
+		}
+
+		if operationBody.Action != model.BatchUpdate {
+			continue
+		}
+
+		operations[i].Patch, err = model.ParseMergePatch(operationBody.Patch)
+		if err != nil {
+			s.handleBodyError(w, err)
+			return
+		}
+
+		operations[i].Patch.Task.Version = operationBody.Version
+	}
+
+	// Batches run in their own transaction, which an atomic batch that was not
+	// applied rolls back while still responding with its results.
+	response, err := s.repo.Batch(r.Context(), operations, body.Atomic, actorOf(r))
+	if notApplied, ok := model.NotAppliedBatch(err); ok {
+		response, err = notApplied, nil
+	}
+
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	str, err := json.Marshal(response)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(str)
+}
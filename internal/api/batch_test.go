@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mtbuzato/go-challenge/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPOSTBatch(t *testing.T) {
+	tests := map[string]struct {
+		body             string
+		expectedStatus   int
+		expectedResponse model.BatchResponse
+		expectedTasks    []model.Task
+	}{
+		"Apply every kind of operation": {
+			body: `{"operations":[
+				{"action":"create","task":{"name":"Task 4"}},
+				{"action":"update","id":"1","patch":{"completed":true}},
+				{"action":"delete","id":"2"}
+			]}`,
+			expectedStatus: http.StatusOK,
+			expectedResponse: model.BatchResponse{
+				Applied: true,
+				Results: []model.BatchResult{{ID: "4"}, {ID: "1"}, {ID: "2"}},
+			},
+			expectedTasks: []model.Task{
+				{ID: "1", Name: "Task 1", Completed: true, Version: 2},
+			},
+		},
+		"Report failed operations": {
+			body: `{"operations":[
+				{"action":"update","id":"1","patch":{"name":"Task 1 Updated"}},
+				{"action":"delete","id":"3"}
+			]}`,
+			expectedStatus: http.StatusOK,
+			expectedResponse: model.BatchResponse{
+				Applied: true,
+				Results: []model.BatchResult{{ID: "1"}, {ID: "3", Error: "Task not found."}},
+			},
+			expectedTasks: []model.Task{
+				{ID: "1", Name: "Task 1 Updated", Version: 2},
+				{ID: "2", Name: "Task 2", Completed: true, Version: 1},
+			},
+		},
+		"Leave tasks untouched when an atomic batch fails": {
+			body: `{"atomic":true,"operations":[
+				{"action":"update","id":"1","patch":{"name":"Task 1 Updated"}},
+				{"action":"update","id":"2","patch":{"name":"Task 2 Updated"},"version":3}
+			]}`,
+			expectedStatus: http.StatusOK,
+			expectedResponse: model.BatchResponse{
+				Applied: false,
+				Results: []model.BatchResult{{ID: "1"}, {ID: "2", Error: "Task has been changed since it was read."}},
+			},
+			expectedTasks: []model.Task{
+				{ID: "1", Name: "Task 1", Version: 1},
+				{ID: "2", Name: "Task 2", Completed: true, Version: 1},
+			},
+		},
+		"Empty batch": {
+			body:           `{"operations":[]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Invalid action": {
+			body:           `{"operations":[{"action":"archive","id":"1"}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Invalid patch": {
+			body:           `{"operations":[{"action":"update","id":"1","patch":{"project_id":"1"}}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			repo := &StubTaskRepository{
+				tasks: []model.Task{
+					{ID: "1", Name: "Task 1", Version: 1},
+					{ID: "2", Name: "Task 2", Completed: true, Version: 1},
+				},
+			}
+			server := NewAPIServer(repo)
+
+			req, err := http.NewRequest("POST", "/tasks:batch", strings.NewReader(test.body))
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+
+			if test.expectedStatus == http.StatusOK {
+				var response model.BatchResponse
+				err = json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(err)
+				assert.Equal(test.expectedResponse, response)
+				assert.Equal(test.expectedTasks, repo.tasks)
+			}
+		})
+	}
+}
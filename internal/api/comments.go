@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+func (s *apiServer) handleTaskComment(w http.ResponseWriter, r *http.Request, taskId string, commentId string) {
+	switch r.Method {
+	case "DELETE":
+		s.deleteTaskComment(w, r, taskId, commentId)
+	default:
+		s.handleNotFound(w, r)
+	}
+}
+
+func (s *apiServer) getTaskComments(w http.ResponseWriter, r *http.Request, id string) {
+	comments, err := s.repo.ListComments(r.Context(), id)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	str, err := json.Marshal(comments)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(str)
+}
+
+type PostTaskCommentBody struct {
+	Author string `json:"author"`
+	Body   string `json:"body"`
+}
+
+func (s *apiServer) postTaskComment(w http.ResponseWriter, r *http.Request, id string) {
+	var commentBody PostTaskCommentBody
+
+	err := json.NewDecoder(r.Body).Decode(&commentBody)
+	if err != nil {
+		s.handleBodyError(w, err)
+		return
+	}
+
+	comment, err := s.repo.CreateComment(r.Context(), model.Comment{
+		TaskID: id,
+		Author: commentBody.Author,
+		Body:   commentBody.Body,
+	})
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	str, err := json.Marshal(comment)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write(str)
+}
+
+func (s *apiServer) deleteTaskComment(w http.ResponseWriter, r *http.Request, id string, commentId string) {
+	err := s.repo.DeleteComment(r.Context(), id, commentId)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mtbuzato/go-challenge/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	commentedTaskID = "cl09rb83d000009l13y5n5ur1"
+	commentID       = "cl09rb83d000009l13y5n5cm1"
+)
+
+func newCommentStubRepository() *StubTaskRepository {
+	return &StubTaskRepository{
+		tasks: []model.Task{
+			{ID: commentedTaskID, Name: "Task 1", Completed: false},
+			{ID: "cl09rb83d000009l13y5n5ur2", Name: "Task 2", Completed: false},
+		},
+		comments: []model.Comment{
+			{ID: commentID, TaskID: commentedTaskID, Author: "Alice", Body: "Looks good to me."},
+		},
+	}
+}
+
+func TestGETTaskComments(t *testing.T) {
+	repo := newCommentStubRepository()
+	server := NewAPIServer(repo)
+
+	tests := map[string]struct {
+		id               string
+		expectedStatus   int
+		expectedComments []model.Comment
+	}{
+		"Get the comments of a task": {
+			id:               commentedTaskID,
+			expectedStatus:   http.StatusOK,
+			expectedComments: repo.comments,
+		},
+		"Get the comments of a task without any": {
+			id:               "cl09rb83d000009l13y5n5ur2",
+			expectedStatus:   http.StatusOK,
+			expectedComments: []model.Comment{},
+		},
+		"Get the comments of a task that does not exist": {
+			id:             "cl09rb83d000009l13y5n5ur3",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			req, err := http.NewRequest("GET", "/tasks/"+test.id+"/comments", nil)
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+
+			if test.expectedStatus == http.StatusOK {
+				var comments []model.Comment
+				err = json.Unmarshal(w.Body.Bytes(), &comments)
+				assert.NoError(err)
+				assert.Equal(test.expectedComments, comments)
+			}
+		})
+	}
+}
+
+func TestPOSTTaskComment(t *testing.T) {
+	tests := map[string]struct {
+		id             string
+		body           string
+		expectedStatus int
+	}{
+		"Comment on a task": {
+			id:             commentedTaskID,
+			body:           `{"author":"Bob","body":"Agreed."}`,
+			expectedStatus: http.StatusCreated,
+		},
+		"Comment without an author": {
+			id:             commentedTaskID,
+			body:           `{"body":"Agreed."}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Comment without a body": {
+			id:             commentedTaskID,
+			body:           `{"author":"Bob"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Comment on a task that does not exist": {
+			id:             "cl09rb83d000009l13y5n5ur3",
+			body:           `{"author":"Bob","body":"Agreed."}`,
+			expectedStatus: http.StatusNotFound,
+		},
+		"Comment with invalid JSON": {
+			id:             commentedTaskID,
+			body:           `{"author":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			server := NewAPIServer(newCommentStubRepository())
+
+			req, err := http.NewRequest("POST", "/tasks/"+test.id+"/comments", strings.NewReader(test.body))
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+
+			if test.expectedStatus == http.StatusCreated {
+				var comment model.Comment
+				err = json.Unmarshal(w.Body.Bytes(), &comment)
+				assert.NoError(err)
+				assert.Equal(test.id, comment.TaskID)
+				assert.Equal("Bob", comment.Author)
+				assert.NoError(model.ValidateCommentID(comment.ID))
+			}
+		})
+	}
+}
+
+func TestDELETETaskComment(t *testing.T) {
+	tests := map[string]struct {
+		path           string
+		expectedStatus int
+	}{
+		"Delete a comment": {
+			path:           "/tasks/" + commentedTaskID + "/comments/" + commentID,
+			expectedStatus: http.StatusNoContent,
+		},
+		"Delete a comment through another task": {
+			path:           "/tasks/cl09rb83d000009l13y5n5ur2/comments/" + commentID,
+			expectedStatus: http.StatusNotFound,
+		},
+		"Delete a comment that does not exist": {
+			path:           "/tasks/" + commentedTaskID + "/comments/cl09rb83d000009l13y5n5cm2",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			server := NewAPIServer(newCommentStubRepository())
+
+			req, err := http.NewRequest("DELETE", test.path, nil)
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+		})
+	}
+}
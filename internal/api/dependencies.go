@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func (s *apiServer) handleTaskDependency(w http.ResponseWriter, r *http.Request, taskId string, blockerId string) {
+	switch r.Method {
+	case "DELETE":
+		s.deleteTaskDependency(w, r, taskId, blockerId)
+	default:
+		s.handleNotFound(w, r)
+	}
+}
+
+func (s *apiServer) getTaskDependencies(w http.ResponseWriter, r *http.Request, id string) {
+	dependencies, err := s.repo.ListDependencies(r.Context(), id)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	if err := renderTasks(r, dependencies.BlockedBy); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	if err := renderTasks(r, dependencies.Blocks); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	str, err := json.Marshal(dependencies)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(str)
+}
+
+type PostTaskDependencyBody struct {
+	BlockerID string `json:"blocker_id"`
+}
+
+func (s *apiServer) postTaskDependency(w http.ResponseWriter, r *http.Request, id string) {
+	var dependencyBody PostTaskDependencyBody
+
+	err := json.NewDecoder(r.Body).Decode(&dependencyBody)
+	if err != nil {
+		s.handleBodyError(w, err)
+		return
+	}
+
+	err = s.repo.AddDependency(r.Context(), id, dependencyBody.BlockerID)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *apiServer) deleteTaskDependency(w http.ResponseWriter, r *http.Request, id string, blockerId string) {
+	err := s.repo.RemoveDependency(r.Context(), id, blockerId)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
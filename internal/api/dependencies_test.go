@@ -0,0 +1,167 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mtbuzato/go-challenge/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	blockedTaskID = "cl09rb83d000009l13y5n5ur1"
+	blockerTaskID = "cl09rb83d000009l13y5n5ur2"
+	freeTaskID    = "cl09rb83d000009l13y5n5ur3"
+)
+
+func newBlockedStubRepository() *StubTaskRepository {
+	return &StubTaskRepository{
+		tasks: []model.Task{
+			{ID: blockedTaskID, Name: "Task 1", Completed: false},
+			{ID: blockerTaskID, Name: "Task 2", Completed: false},
+			{ID: freeTaskID, Name: "Task 3", Completed: false},
+		},
+		blockers: map[string][]string{
+			blockedTaskID: {blockerTaskID},
+		},
+	}
+}
+
+func TestGETTaskDependencies(t *testing.T) {
+	repo := newBlockedStubRepository()
+	server := NewAPIServer(repo)
+
+	tests := map[string]struct {
+		id                   string
+		expectedStatus       int
+		expectedDependencies model.TaskDependencies
+	}{
+		"Get the dependencies of a blocked task": {
+			id:             blockedTaskID,
+			expectedStatus: http.StatusOK,
+			expectedDependencies: model.TaskDependencies{
+				BlockedBy: []model.Task{repo.tasks[1]},
+				Blocks:    []model.Task{},
+			},
+		},
+		"Get the dependencies of a blocker": {
+			id:             blockerTaskID,
+			expectedStatus: http.StatusOK,
+			expectedDependencies: model.TaskDependencies{
+				BlockedBy: []model.Task{},
+				Blocks:    []model.Task{repo.tasks[0]},
+			},
+		},
+		"Get the dependencies of a task that does not exist": {
+			id:             "cl09rb83d000009l13y5n5ur4",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			req, err := http.NewRequest("GET", "/tasks/"+test.id+"/dependencies", nil)
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+
+			if test.expectedStatus == http.StatusOK {
+				var dependencies model.TaskDependencies
+				err = json.Unmarshal(w.Body.Bytes(), &dependencies)
+				assert.NoError(err)
+				assert.Equal(test.expectedDependencies, dependencies)
+			}
+		})
+	}
+}
+
+func TestPOSTTaskDependency(t *testing.T) {
+	tests := map[string]struct {
+		id             string
+		body           string
+		expectedStatus int
+	}{
+		"Add a dependency": {
+			id:             freeTaskID,
+			body:           `{"blocker_id":"` + blockerTaskID + `"}`,
+			expectedStatus: http.StatusNoContent,
+		},
+		"Add a dependency on itself": {
+			id:             freeTaskID,
+			body:           `{"blocker_id":"` + freeTaskID + `"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Add a dependency that creates a cycle": {
+			id:             blockerTaskID,
+			body:           `{"blocker_id":"` + blockedTaskID + `"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Add a dependency on a task that does not exist": {
+			id:             freeTaskID,
+			body:           `{"blocker_id":"cl09rb83d000009l13y5n5ur4"}`,
+			expectedStatus: http.StatusNotFound,
+		},
+		"Add a dependency with invalid JSON": {
+			id:             freeTaskID,
+			body:           `{"blocker_id":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			server := NewAPIServer(newBlockedStubRepository())
+
+			req, err := http.NewRequest("POST", "/tasks/"+test.id+"/dependencies", strings.NewReader(test.body))
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestDELETETaskDependency(t *testing.T) {
+	tests := map[string]struct {
+		path           string
+		expectedStatus int
+	}{
+		"Remove a dependency": {
+			path:           "/tasks/" + blockedTaskID + "/dependencies/" + blockerTaskID,
+			expectedStatus: http.StatusNoContent,
+		},
+		"Remove a dependency that does not exist": {
+			path:           "/tasks/" + freeTaskID + "/dependencies/" + blockerTaskID,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			server := NewAPIServer(newBlockedStubRepository())
+
+			req, err := http.NewRequest("DELETE", test.path, nil)
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func (s *apiServer) getTaskHistory(w http.ResponseWriter, r *http.Request, id string) {
+	history, err := s.repo.GetHistory(r.Context(), id)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	str, err := json.Marshal(history)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(str)
+}
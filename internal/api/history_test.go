@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mtbuzato/go-challenge/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const historyTaskID = "cl09rb83d000009l13y5n5ur1"
+
+func TestGETTaskHistory(t *testing.T) {
+	tests := map[string]struct {
+		id              string
+		expectedStatus  int
+		expectedActions []model.HistoryAction
+	}{
+		"Get the history of a task": {
+			id:              historyTaskID,
+			expectedStatus:  http.StatusOK,
+			expectedActions: []model.HistoryAction{model.HistoryUpdated},
+		},
+		"Get the history of a task without any": {
+			id:              "cl09rb83d000009l13y5n5ur2",
+			expectedStatus:  http.StatusOK,
+			expectedActions: []model.HistoryAction{},
+		},
+		"Get the history of a task that does not exist": {
+			id:             "cl09rb83d000009l13y5n5ur3",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			server := NewAPIServer(&StubTaskRepository{
+				tasks: []model.Task{
+					{ID: historyTaskID, Name: "Task 1", Status: model.StatusTodo},
+					{ID: "cl09rb83d000009l13y5n5ur2", Name: "Task 2", Status: model.StatusTodo},
+				},
+			})
+
+			req, err := http.NewRequest("PUT", "/tasks/"+historyTaskID, strings.NewReader(`{"name":"Renamed"}`))
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			req.Header.Set("X-Actor", "alice")
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+			assert.Equal(http.StatusOK, w.Code)
+
+			req, err = http.NewRequest("GET", "/tasks/"+test.id+"/history", nil)
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w = httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+
+			if test.expectedStatus == http.StatusOK {
+				var history []model.HistoryEntry
+				err = json.Unmarshal(w.Body.Bytes(), &history)
+				assert.NoError(err)
+
+				actions := []model.HistoryAction{}
+				for _, entry := range history {
+					actions = append(actions, entry.Action)
+					assert.Equal("alice", entry.Actor)
+				}
+				assert.Equal(test.expectedActions, actions)
+			}
+		})
+	}
+}
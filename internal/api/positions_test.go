@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mtbuzato/go-challenge/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOrderedStubRepository() *StubTaskRepository {
+	return &StubTaskRepository{
+		tasks: []model.Task{
+			{ID: "cl09rb83d000009l13y5n5ur1", Name: "Task 1", Position: "F"},
+			{ID: "cl09rb83d000009l13y5n5ur2", Name: "Task 2", Position: "V"},
+			{ID: "cl09rb83d000009l13y5n5ur3", Name: "Task 3", Position: "l"},
+		},
+	}
+}
+
+func TestPOSTReorderTask(t *testing.T) {
+	tests := map[string]struct {
+		id               string
+		body             string
+		expectedStatus   int
+		expectedPosition string
+	}{
+		"Move a task between two others": {
+			id:               "cl09rb83d000009l13y5n5ur3",
+			body:             `{"after_id":"cl09rb83d000009l13y5n5ur1","before_id":"cl09rb83d000009l13y5n5ur2"}`,
+			expectedStatus:   http.StatusOK,
+			expectedPosition: "N",
+		},
+		"Move a task to the top": {
+			id:               "cl09rb83d000009l13y5n5ur3",
+			body:             `{"before_id":"cl09rb83d000009l13y5n5ur1"}`,
+			expectedStatus:   http.StatusOK,
+			expectedPosition: "8",
+		},
+		"Move a task next to itself": {
+			id:             "cl09rb83d000009l13y5n5ur3",
+			body:           `{"after_id":"cl09rb83d000009l13y5n5ur3"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Move a task next to an invalid task": {
+			id:             "cl09rb83d000009l13y5n5ur3",
+			body:           `{"after_id":"1"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Move a task between neighbors out of order": {
+			id:             "cl09rb83d000009l13y5n5ur3",
+			body:           `{"after_id":"cl09rb83d000009l13y5n5ur2","before_id":"cl09rb83d000009l13y5n5ur1"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Move a task next to a task that does not exist": {
+			id:             "cl09rb83d000009l13y5n5ur3",
+			body:           `{"after_id":"cl09rb83d000009l13y5n5ur4"}`,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			server := NewAPIServer(newOrderedStubRepository())
+
+			req, err := http.NewRequest("POST", "/tasks/"+test.id+"/move", strings.NewReader(test.body))
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+
+			if test.expectedStatus == http.StatusOK {
+				var task model.Task
+				err = json.Unmarshal(w.Body.Bytes(), &task)
+				assert.NoError(err)
+				assert.Equal(test.expectedPosition, task.Position)
+			}
+		})
+	}
+}
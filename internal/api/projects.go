@@ -0,0 +1,201 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+func (s *apiServer) handleProjects(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		s.getProjects(w, r)
+	case "POST":
+		s.postProject(w, r)
+	default:
+		s.handleNotFound(w, r)
+	}
+}
+
+func (s *apiServer) handleProject(w http.ResponseWriter, r *http.Request) {
+	if err := validateRender(r); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	split := strings.Split(r.URL.Path, "/")
+
+	if len(split) == 4 && split[3] == "tasks" {
+		switch r.Method {
+		case "GET":
+			s.getProjectTasks(w, r, split[2])
+		case "POST":
+			s.createTask(w, r, &split[2])
+		default:
+			s.handleNotFound(w, r)
+		}
+		return
+	}
+
+	if len(split) != 3 {
+		s.handleNotFound(w, r)
+		return
+	}
+
+	projectId := split[2]
+
+	switch r.Method {
+	case "GET":
+		s.getProject(w, r, projectId)
+	case "PUT":
+		s.putProject(w, r, projectId)
+	case "DELETE":
+		s.deleteProject(w, r, projectId)
+	default:
+		s.handleNotFound(w, r)
+	}
+}
+
+func (s *apiServer) getProjects(w http.ResponseWriter, r *http.Request) {
+	projects, err := s.repo.ListProjects(r.Context())
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	str, err := json.Marshal(projects)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(str)
+}
+
+func (s *apiServer) getProject(w http.ResponseWriter, r *http.Request, id string) {
+	project, err := s.repo.GetProject(r.Context(), id)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	str, err := json.Marshal(project)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(str)
+}
+
+// Lists a page of the tasks in a project. The same filters as the task
+// listing are accepted.
+func (s *apiServer) getProjectTasks(w http.ResponseWriter, r *http.Request, id string) {
+	_, err := s.repo.GetProject(r.Context(), id)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	query, err := parseTaskQuery(r.URL.Query())
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	query.ProjectID = id
+
+	page, err := s.repo.Query(r.Context(), query)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	if err := renderTasks(r, page.Tasks); err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	str, err := json.Marshal(page)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(str)
+}
+
+type ProjectBody struct {
+	Name string `json:"name"`
+}
+
+func (s *apiServer) postProject(w http.ResponseWriter, r *http.Request) {
+	var projectBody ProjectBody
+
+	err := json.NewDecoder(r.Body).Decode(&projectBody)
+	if err != nil {
+		s.handleBodyError(w, err)
+		return
+	}
+
+	project, err := s.repo.CreateProject(r.Context(), model.Project{Name: projectBody.Name})
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	str, err := json.Marshal(project)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write(str)
+}
+
+func (s *apiServer) putProject(w http.ResponseWriter, r *http.Request, id string) {
+	var projectBody ProjectBody
+
+	err := json.NewDecoder(r.Body).Decode(&projectBody)
+	if err != nil {
+		s.handleBodyError(w, err)
+		return
+	}
+
+	err = s.repo.UpdateProject(r.Context(), model.Project{ID: id, Name: projectBody.Name})
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	project, err := s.repo.GetProject(r.Context(), id)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	str, err := json.Marshal(project)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(str)
+}
+
+func (s *apiServer) deleteProject(w http.ResponseWriter, r *http.Request, id string) {
+	err := s.repo.DeleteProject(r.Context(), id)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
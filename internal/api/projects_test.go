@@ -0,0 +1,231 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mtbuzato/go-challenge/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newProjectStubRepository() *StubTaskRepository {
+	home := "cl09rb83d000009l13y5n5pr1"
+
+	return &StubTaskRepository{
+		tasks: []model.Task{
+			{ID: "cl09rb83d000009l13y5n5ur1", Name: "Task 1", ProjectID: &home},
+			{ID: "cl09rb83d000009l13y5n5ur2", Name: "Task 2"},
+		},
+		projects: []model.Project{
+			{ID: home, Name: "Home"},
+			{ID: "cl09rb83d000009l13y5n5pr2", Name: "Work"},
+		},
+	}
+}
+
+func TestGETProjects(t *testing.T) {
+	assert := assert.New(t)
+	repo := newProjectStubRepository()
+	server := NewAPIServer(repo)
+
+	req, err := http.NewRequest("GET", "/projects", nil)
+	req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+	assert.NoError(err)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(http.StatusOK, w.Code)
+
+	var projects []model.Project
+	err = json.Unmarshal(w.Body.Bytes(), &projects)
+	assert.NoError(err)
+	assert.Equal(repo.projects, projects)
+}
+
+func TestGETProjectTasks(t *testing.T) {
+	repo := newProjectStubRepository()
+	server := NewAPIServer(repo)
+
+	tests := map[string]struct {
+		id             string
+		expectedStatus int
+		expectedPage   model.TaskPage
+	}{
+		"List the tasks of a project": {
+			id:             "cl09rb83d000009l13y5n5pr1",
+			expectedStatus: http.StatusOK,
+			expectedPage:   model.TaskPage{Tasks: repo.tasks[:1]},
+		},
+		"List the tasks of an empty project": {
+			id:             "cl09rb83d000009l13y5n5pr2",
+			expectedStatus: http.StatusOK,
+			expectedPage:   model.TaskPage{Tasks: []model.Task{}},
+		},
+		"List the tasks of a project that does not exist": {
+			id:             "cl09rb83d000009l13y5n5pr3",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			req, err := http.NewRequest("GET", "/projects/"+test.id+"/tasks", nil)
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+
+			if test.expectedStatus == http.StatusOK {
+				var page model.TaskPage
+				err = json.Unmarshal(w.Body.Bytes(), &page)
+				assert.NoError(err)
+				assert.Equal(test.expectedPage, page)
+			}
+		})
+	}
+}
+
+func TestPOSTProjectTask(t *testing.T) {
+	assert := assert.New(t)
+	repo := newProjectStubRepository()
+	server := NewAPIServer(repo)
+
+	req, err := http.NewRequest("POST", "/projects/cl09rb83d000009l13y5n5pr2/tasks", strings.NewReader(`{"name":"Task 4"}`))
+	req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+	assert.NoError(err)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(http.StatusCreated, w.Code)
+
+	var task model.Task
+	err = json.Unmarshal(w.Body.Bytes(), &task)
+	assert.NoError(err)
+	assert.Equal("cl09rb83d000009l13y5n5pr2", *task.ProjectID)
+}
+
+func TestProjectCRUD(t *testing.T) {
+	tests := map[string]struct {
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		"Create a project": {
+			method:         "POST",
+			path:           "/projects",
+			body:           `{"name":"Errands"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		"Create a project without a name": {
+			method:         "POST",
+			path:           "/projects",
+			body:           `{"name":""}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Get a project": {
+			method:         "GET",
+			path:           "/projects/cl09rb83d000009l13y5n5pr1",
+			expectedStatus: http.StatusOK,
+		},
+		"Get a project that does not exist": {
+			method:         "GET",
+			path:           "/projects/cl09rb83d000009l13y5n5pr3",
+			expectedStatus: http.StatusNotFound,
+		},
+		"Rename a project": {
+			method:         "PUT",
+			path:           "/projects/cl09rb83d000009l13y5n5pr1",
+			body:           `{"name":"House"}`,
+			expectedStatus: http.StatusOK,
+		},
+		"Delete a project": {
+			method:         "DELETE",
+			path:           "/projects/cl09rb83d000009l13y5n5pr1",
+			expectedStatus: http.StatusNoContent,
+		},
+		"Delete a project that does not exist": {
+			method:         "DELETE",
+			path:           "/projects/cl09rb83d000009l13y5n5pr3",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			server := NewAPIServer(newProjectStubRepository())
+
+			req, err := http.NewRequest(test.method, test.path, strings.NewReader(test.body))
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestPOSTMoveTask(t *testing.T) {
+	work := "cl09rb83d000009l13y5n5pr2"
+
+	tests := map[string]struct {
+		id                string
+		body              string
+		expectedStatus    int
+		expectedProjectID *string
+	}{
+		"Move a task to another project": {
+			id:                "cl09rb83d000009l13y5n5ur1",
+			body:              `{"project_id":"` + work + `"}`,
+			expectedStatus:    http.StatusOK,
+			expectedProjectID: &work,
+		},
+		"Move a task out of its project": {
+			id:                "cl09rb83d000009l13y5n5ur1",
+			body:              `{"project_id":null}`,
+			expectedStatus:    http.StatusOK,
+			expectedProjectID: nil,
+		},
+		"Move a task to a project that does not exist": {
+			id:             "cl09rb83d000009l13y5n5ur2",
+			body:           `{"project_id":"cl09rb83d000009l13y5n5pr3"}`,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			server := NewAPIServer(newProjectStubRepository())
+
+			req, err := http.NewRequest("POST", "/tasks/"+test.id+"/move", strings.NewReader(test.body))
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+
+			if test.expectedStatus == http.StatusOK {
+				var task model.Task
+				err = json.Unmarshal(w.Body.Bytes(), &task)
+				assert.NoError(err)
+				assert.Equal(test.expectedProjectID, task.ProjectID)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func (s *apiServer) handleTags(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		s.getTags(w, r)
+	default:
+		s.handleNotFound(w, r)
+	}
+}
+
+func (s *apiServer) handleTaskTag(w http.ResponseWriter, r *http.Request, taskId string, tag string) {
+	switch r.Method {
+	case "DELETE":
+		s.deleteTaskTag(w, r, taskId, tag)
+	default:
+		s.handleNotFound(w, r)
+	}
+}
+
+func (s *apiServer) getTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := s.repo.ListTags(r.Context())
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	str, err := json.Marshal(tags)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(str)
+}
+
+func (s *apiServer) getTaskTags(w http.ResponseWriter, r *http.Request, id string) {
+	tags, err := s.repo.ListTaskTags(r.Context(), id)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	str, err := json.Marshal(tags)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(str)
+}
+
+type PostTaskTagBody struct {
+	Name string `json:"name"`
+}
+
+func (s *apiServer) postTaskTag(w http.ResponseWriter, r *http.Request, id string) {
+	var tagBody PostTaskTagBody
+
+	err := json.NewDecoder(r.Body).Decode(&tagBody)
+	if err != nil {
+		s.handleBodyError(w, err)
+		return
+	}
+
+	tag, err := s.repo.AttachTag(r.Context(), id, tagBody.Name)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	str, err := json.Marshal(tag)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write(str)
+}
+
+func (s *apiServer) deleteTaskTag(w http.ResponseWriter, r *http.Request, id string, tag string) {
+	err := s.repo.DetachTag(r.Context(), id, tag)
+	if err != nil {
+		s.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mtbuzato/go-challenge/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTaggedStubRepository() *StubTaskRepository {
+	return &StubTaskRepository{
+		tasks: []model.Task{
+			{ID: "1", Name: "Task 1", Completed: false},
+			{ID: "2", Name: "Task 2", Completed: true},
+		},
+		tags: map[string][]string{
+			"1": {"backend", "urgent"},
+			"2": {"backend"},
+		},
+	}
+}
+
+func TestGETTags(t *testing.T) {
+	assert := assert.New(t)
+	server := NewAPIServer(newTaggedStubRepository())
+
+	req, err := http.NewRequest("GET", "/tags", nil)
+	req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+	assert.NoError(err)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(http.StatusOK, w.Code)
+
+	var tags []model.Tag
+	err = json.Unmarshal(w.Body.Bytes(), &tags)
+	assert.NoError(err)
+	assert.Equal([]model.Tag{{ID: "backend", Name: "backend"}, {ID: "urgent", Name: "urgent"}}, tags)
+}
+
+func TestGETTaskTags(t *testing.T) {
+	server := NewAPIServer(newTaggedStubRepository())
+
+	tests := map[string]struct {
+		id             string
+		expectedStatus int
+		expectedTags   []model.Tag
+	}{
+		"Get the tags of a task": {
+			id:             "2",
+			expectedStatus: http.StatusOK,
+			expectedTags:   []model.Tag{{ID: "backend", Name: "backend"}},
+		},
+		"Get the tags of a task that does not exist": {
+			id:             "3",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			req, err := http.NewRequest("GET", "/tasks/"+test.id+"/tags", nil)
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+
+			if test.expectedStatus == http.StatusOK {
+				var tags []model.Tag
+				err = json.Unmarshal(w.Body.Bytes(), &tags)
+				assert.NoError(err)
+				assert.Equal(test.expectedTags, tags)
+			}
+		})
+	}
+}
+
+func TestPOSTTaskTag(t *testing.T) {
+	tests := map[string]struct {
+		id             string
+		body           string
+		expectedStatus int
+		expectedTag    model.Tag
+	}{
+		"Attach a tag": {
+			id:             "2",
+			body:           `{"name":"Waiting"}`,
+			expectedStatus: http.StatusCreated,
+			expectedTag:    model.Tag{ID: "waiting", Name: "waiting"},
+		},
+		"Attach an invalid tag": {
+			id:             "2",
+			body:           `{"name":"needs review"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"Attach a tag to a task that does not exist": {
+			id:             "3",
+			body:           `{"name":"waiting"}`,
+			expectedStatus: http.StatusNotFound,
+		},
+		"Attach a tag with invalid JSON": {
+			id:             "2",
+			body:           `{"name":"waiting`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			server := NewAPIServer(newTaggedStubRepository())
+
+			req, err := http.NewRequest("POST", "/tasks/"+test.id+"/tags", strings.NewReader(test.body))
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+
+			if test.expectedStatus == http.StatusCreated {
+				var tag model.Tag
+				err = json.Unmarshal(w.Body.Bytes(), &tag)
+				assert.NoError(err)
+				assert.Equal(test.expectedTag, tag)
+			}
+		})
+	}
+}
+
+func TestDELETETaskTag(t *testing.T) {
+	tests := map[string]struct {
+		path           string
+		expectedStatus int
+	}{
+		"Detach a tag": {
+			path:           "/tasks/1/tags/urgent",
+			expectedStatus: http.StatusNoContent,
+		},
+		"Detach a tag the task does not have": {
+			path:           "/tasks/2/tags/urgent",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			server := NewAPIServer(newTaggedStubRepository())
+
+			req, err := http.NewRequest("DELETE", test.path, nil)
+			req.Header.Set("Authorization", "Bearer "+os.Getenv("API_KEY"))
+			assert.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(test.expectedStatus, w.Code)
+		})
+	}
+}
@@ -3,17 +3,54 @@ package apigrpc
 import (
 	context "context"
 	"fmt"
+	"io"
+	"strings"
 
 	empty "github.com/golang/protobuf/ptypes/empty"
+	"github.com/mtbuzato/go-challenge/internal/errors"
 	"github.com/mtbuzato/go-challenge/internal/model"
+	codes "google.golang.org/grpc/codes"
+	metadata "google.golang.org/grpc/metadata"
+	status "google.golang.org/grpc/status"
 )
 
+// Every method takes the context of the call it serves, so that queries are
+// cancelled along with the call or when its deadline expires.
 type TaskRepository interface {
-	ListAll() ([]model.Task, error)
-	ListByCompletion(completed bool) ([]model.Task, error)
-	Create(name string) (model.Task, error)
-	GetByID(id string) (model.Task, error)
-	Update(task model.Task) error
+	ListAll(ctx context.Context) ([]model.Task, error)
+	ListByCompletion(ctx context.Context, completed bool) ([]model.Task, error)
+	Query(ctx context.Context, query model.TaskQuery) (model.TaskPage, error)
+	Search(ctx context.Context, query string, limit int) ([]model.Task, error)
+	Create(ctx context.Context, task model.Task, actor string) (model.Task, error)
+	GetByID(ctx context.Context, id string) (model.Task, error)
+	GetTree(ctx context.Context, id string) (model.TaskTree, error)
+	Update(ctx context.Context, task model.Task, actor string) error
+	Patch(ctx context.Context, id string, patch model.TaskPatch, actor string) error
+	Batch(ctx context.Context, operations []model.BatchOperation, atomic bool, actor string) (model.BatchResponse, error)
+	Delete(ctx context.Context, id string, actor string) error
+	ListTags(ctx context.Context) ([]model.Tag, error)
+	ListTaskTags(ctx context.Context, id string) ([]model.Tag, error)
+	AttachTag(ctx context.Context, id string, name string) (model.Tag, error)
+	DetachTag(ctx context.Context, id string, name string) error
+	ListProjects(ctx context.Context) ([]model.Project, error)
+	GetProject(ctx context.Context, id string) (model.Project, error)
+	CreateProject(ctx context.Context, project model.Project) (model.Project, error)
+	UpdateProject(ctx context.Context, project model.Project) error
+	DeleteProject(ctx context.Context, id string) error
+	MoveTask(ctx context.Context, id string, projectID *string, actor string) error
+	ReorderTask(ctx context.Context, id string, afterID *string, beforeID *string) error
+	ListDependencies(ctx context.Context, id string) (model.TaskDependencies, error)
+	AddDependency(ctx context.Context, id string, blockerID string) error
+	RemoveDependency(ctx context.Context, id string, blockerID string) error
+	ListComments(ctx context.Context, id string) ([]model.Comment, error)
+	CreateComment(ctx context.Context, comment model.Comment) (model.Comment, error)
+	DeleteComment(ctx context.Context, id string, commentID string) error
+	ListAttachments(ctx context.Context, id string) ([]model.Attachment, error)
+	OpenAttachment(ctx context.Context, id string, attachmentID string) (model.Attachment, io.ReadCloser, error)
+	CreateAttachment(ctx context.Context, attachment model.Attachment, content io.Reader) (model.Attachment, error)
+	DeleteAttachment(ctx context.Context, id string, attachmentID string) error
+	GetHistory(ctx context.Context, id string) ([]model.HistoryEntry, error)
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type grpcServer struct {
@@ -29,10 +66,66 @@ func NewGRPCServer(repo TaskRepository) *grpcServer {
 	return server
 }
 
-func (s *grpcServer) ListTasks(_ *empty.Empty, stream TaskService_ListTasksServer) error {
-	tasks, err := s.repo.ListAll()
+// Converts an error into a gRPC status, mapping external errors to the
+// matching status code, calls cancelled or timed out while querying the
+// database to the status of their context and everything else to an internal
+// error.
+func handleError(method string, err error) error {
+	code := codes.Internal
+
+	if errors.IsConflict(err) {
+		code = codes.Aborted
+	} else if errors.IsExternal(err) {
+		if strings.Contains(strings.ToLower(err.Error()), "not found") {
+			code = codes.NotFound
+		} else {
+			code = codes.InvalidArgument
+		}
+	} else if c := status.FromContextError(err).Code(); c != codes.Unknown {
+		code = c
+	}
+
+	return status.Errorf(code, "%s: %v", method, err)
+}
+
+// Returns who is making a call, as identified by the optional "actor"
+// metadata. It is recorded in the history of the tasks the call changes.
+func actorOf(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if values := md.Get("actor"); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+func (s *grpcServer) ListTasks(req *ListTasksRequest, stream TaskService_ListTasksServer) error {
+	var tasks []model.Task
+	var err error
+
+	if req.GetPageSize() == 0 && req.GetPageToken() == "" {
+		tasks, err = s.repo.ListAll(stream.Context())
+	} else {
+		var page model.TaskPage
+		page, err = s.repo.Query(stream.Context(), model.TaskQuery{
+			Page: model.PageRequest{
+				Limit:  int(req.GetPageSize()),
+				Cursor: req.GetPageToken(),
+			},
+		})
+
+		if err == nil {
+			tasks = page.Tasks
+			stream.SetTrailer(metadata.Pairs("next-page-token", page.NextCursor))
+		}
+	}
+
 	if err != nil {
-		return fmt.Errorf("grpc.ListTasks: %v", err)
+		return handleError("grpc.ListTasks", err)
 	}
 
 	for _, task := range tasks {
@@ -45,9 +138,9 @@ func (s *grpcServer) ListTasks(_ *empty.Empty, stream TaskService_ListTasksServe
 }
 
 func (s *grpcServer) ListTasksByCompletion(req *ListTasksByCompletionRequest, stream TaskService_ListTasksByCompletionServer) error {
-	tasks, err := s.repo.ListByCompletion(req.GetCompleted())
+	tasks, err := s.repo.ListByCompletion(stream.Context(), req.GetCompleted())
 	if err != nil {
-		return fmt.Errorf("grpc.ListTasksByCompletion: %v", err)
+		return handleError("grpc.ListTasksByCompletion", err)
 	}
 
 	for _, task := range tasks {
@@ -59,30 +152,244 @@ func (s *grpcServer) ListTasksByCompletion(req *ListTasksByCompletionRequest, st
 	return nil
 }
 
-func (s *grpcServer) GetTaskByID(_ context.Context, req *GetTaskByIDRequest) (*Task, error) {
-	task, err := s.repo.GetByID(req.GetId())
+func (s *grpcServer) QueryTasks(ctx context.Context, req *QueryTasksRequest) (*QueryTasksResponse, error) {
+	page, err := s.repo.Query(ctx, queryBtoa(req))
+	if err != nil {
+		return nil, handleError("grpc.QueryTasks", err)
+	}
+
+	res := &QueryTasksResponse{NextPageToken: page.NextCursor}
+	for _, task := range page.Tasks {
+		res.Tasks = append(res.Tasks, taskAtob(task))
+	}
+
+	return res, nil
+}
+
+func (s *grpcServer) SearchTasks(req *SearchTasksRequest, stream TaskService_SearchTasksServer) error {
+	tasks, err := s.repo.Search(stream.Context(), req.GetQuery(), int(req.GetLimit()))
+	if err != nil {
+		return handleError("grpc.SearchTasks", err)
+	}
+
+	for _, task := range tasks {
+		if err := stream.Send(taskAtob(task)); err != nil {
+			return fmt.Errorf("grpc.SearchTasks: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *grpcServer) GetTaskByID(ctx context.Context, req *GetTaskByIDRequest) (*Task, error) {
+	task, err := s.repo.GetByID(ctx, req.GetId())
+	if err != nil {
+		return nil, handleError("grpc.GetTaskByID", err)
+	}
+
+	return taskAtob(task), nil
+}
+
+func (s *grpcServer) GetTaskTree(ctx context.Context, req *GetTaskByIDRequest) (*TaskTree, error) {
+	tree, err := s.repo.GetTree(ctx, req.GetId())
+	if err != nil {
+		return nil, handleError("grpc.GetTaskTree", err)
+	}
+
+	return treeAtob(tree), nil
+}
+
+func (s *grpcServer) CreateTask(ctx context.Context, req *CreateTaskRequest) (*Task, error) {
+	task := model.Task{
+		Name:         req.GetName(),
+		Description:  req.GetDescription(),
+		Status:       model.Status(req.GetStatus()),
+		Priority:     model.Priority(req.GetPriority()),
+		ProjectID:    idBtoa(req.GetProjectId()),
+		ParentID:     idBtoa(req.GetParentId()),
+		AutoComplete: req.GetAutoComplete(),
+		Recurrence:   req.GetRecurrence(),
+		DueAt:        timeBtoa(req.GetDueAt()),
+	}
+
+	err := s.repo.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		task, err = s.repo.Create(ctx, task, actorOf(ctx))
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("grpc.GetTaskByID: %v", err)
+		return nil, handleError("grpc.CreateTask", err)
 	}
 
 	return taskAtob(task), nil
 }
 
-func (s *grpcServer) CreateTask(_ context.Context, req *CreateTaskRequest) (*Task, error) {
-	task, err := s.repo.Create(req.Name)
+func (s *grpcServer) UpdateTask(ctx context.Context, task *Task) (*Task, error) {
+	err := s.repo.Transaction(ctx, func(ctx context.Context) error {
+		return s.repo.Update(ctx, taskBtoa(task), actorOf(ctx))
+	})
+	if err != nil {
+		return nil, handleError("grpc.UpdateTask", err)
+	}
+
+	updated, err := s.repo.GetByID(ctx, task.GetId())
+	if err != nil {
+		return nil, handleError("grpc.UpdateTask", err)
+	}
+
+	return taskAtob(updated), nil
+}
+
+func (s *grpcServer) PatchTask(ctx context.Context, req *PatchTaskRequest) (*Task, error) {
+	task := req.GetTask()
+	if task == nil {
+		task = &Task{}
+	}
+
+	patch := model.TaskPatch{
+		Fields: req.GetUpdateMask().GetPaths(),
+		Task:   taskBtoa(task),
+	}
+
+	err := s.repo.Transaction(ctx, func(ctx context.Context) error {
+		return s.repo.Patch(ctx, task.GetId(), patch, actorOf(ctx))
+	})
+	if err != nil {
+		return nil, handleError("grpc.PatchTask", err)
+	}
+
+	patched, err := s.repo.GetByID(ctx, task.GetId())
+	if err != nil {
+		return nil, handleError("grpc.PatchTask", err)
+	}
+
+	return taskAtob(patched), nil
+}
+
+func (s *grpcServer) BatchTasks(ctx context.Context, req *BatchTasksRequest) (*BatchTasksResponse, error) {
+	response, err := s.repo.Batch(ctx, batchBtoa(req), req.GetAtomic(), actorOf(ctx))
+	if notApplied, ok := model.NotAppliedBatch(err); ok {
+		response, err = notApplied, nil
+	}
+
+	if err != nil {
+		return nil, handleError("grpc.BatchTasks", err)
+	}
+
+	return batchAtob(response), nil
+}
+
+func (s *grpcServer) DeleteTask(ctx context.Context, req *DeleteTaskRequest) (*empty.Empty, error) {
+	err := s.repo.Transaction(ctx, func(ctx context.Context) error {
+		return s.repo.Delete(ctx, req.GetId(), actorOf(ctx))
+	})
+	if err != nil {
+		return nil, handleError("grpc.DeleteTask", err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (s *grpcServer) MoveTask(ctx context.Context, req *MoveTaskRequest) (*Task, error) {
+	err := s.repo.Transaction(ctx, func(ctx context.Context) error {
+		if req.GetAfterId() != "" || req.GetBeforeId() != "" {
+			return s.repo.ReorderTask(ctx, req.GetTaskId(), idBtoa(req.GetAfterId()), idBtoa(req.GetBeforeId()))
+		}
+
+		return s.repo.MoveTask(ctx, req.GetTaskId(), idBtoa(req.GetProjectId()), actorOf(ctx))
+	})
+	if err != nil {
+		return nil, handleError("grpc.MoveTask", err)
+	}
+
+	task, err := s.repo.GetByID(ctx, req.GetTaskId())
 	if err != nil {
-		return nil, fmt.Errorf("grpc.CreateTask: %v", err)
+		return nil, handleError("grpc.MoveTask", err)
 	}
 
 	return taskAtob(task), nil
 }
 
-func (s *grpcServer) UpdateTask(_ context.Context, task *Task) (*Task, error) {
-	t := taskBtoa(task)
-	err := s.repo.Update(t)
+func (s *grpcServer) ListTags(_ *empty.Empty, stream TaskService_ListTagsServer) error {
+	tags, err := s.repo.ListTags(stream.Context())
+	if err != nil {
+		return handleError("grpc.ListTags", err)
+	}
+
+	for _, tag := range tags {
+		if err := stream.Send(tagAtob(tag)); err != nil {
+			return fmt.Errorf("grpc.ListTags: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *grpcServer) ListTaskTags(req *ListTaskTagsRequest, stream TaskService_ListTaskTagsServer) error {
+	tags, err := s.repo.ListTaskTags(stream.Context(), req.GetTaskId())
+	if err != nil {
+		return handleError("grpc.ListTaskTags", err)
+	}
+
+	for _, tag := range tags {
+		if err := stream.Send(tagAtob(tag)); err != nil {
+			return fmt.Errorf("grpc.ListTaskTags: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *grpcServer) AttachTag(ctx context.Context, req *TaskTagRequest) (*Tag, error) {
+	tag, err := s.repo.AttachTag(ctx, req.GetTaskId(), req.GetName())
+	if err != nil {
+		return nil, handleError("grpc.AttachTag", err)
+	}
+
+	return tagAtob(tag), nil
+}
+
+func (s *grpcServer) DetachTag(ctx context.Context, req *TaskTagRequest) (*empty.Empty, error) {
+	err := s.repo.DetachTag(ctx, req.GetTaskId(), req.GetName())
+	if err != nil {
+		return nil, handleError("grpc.DetachTag", err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (s *grpcServer) GetTaskDependencies(ctx context.Context, req *GetTaskByIDRequest) (*TaskDependencies, error) {
+	dependencies, err := s.repo.ListDependencies(ctx, req.GetId())
+	if err != nil {
+		return nil, handleError("grpc.GetTaskDependencies", err)
+	}
+
+	return dependenciesAtob(dependencies), nil
+}
+
+func (s *grpcServer) AddDependency(ctx context.Context, req *DependencyRequest) (*empty.Empty, error) {
+	err := s.repo.AddDependency(ctx, req.GetTaskId(), req.GetBlockerId())
+	if err != nil {
+		return nil, handleError("grpc.AddDependency", err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (s *grpcServer) RemoveDependency(ctx context.Context, req *DependencyRequest) (*empty.Empty, error) {
+	err := s.repo.RemoveDependency(ctx, req.GetTaskId(), req.GetBlockerId())
+	if err != nil {
+		return nil, handleError("grpc.RemoveDependency", err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (s *grpcServer) GetTaskHistory(ctx context.Context, req *GetTaskByIDRequest) (*TaskHistory, error) {
+	history, err := s.repo.GetHistory(ctx, req.GetId())
 	if err != nil {
-		return nil, fmt.Errorf("grpc.UpdateTask: %v", err)
+		return nil, handleError("grpc.GetTaskHistory", err)
 	}
 
-	return taskAtob(t), nil
+	return historyAtob(history), nil
 }
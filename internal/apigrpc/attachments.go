@@ -0,0 +1,127 @@
+package apigrpc
+
+import (
+	context "context"
+	"fmt"
+	"io"
+
+	empty "github.com/golang/protobuf/ptypes/empty"
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+// Size of the chunks attachments are downloaded in.
+const attachmentChunkSize = 64 << 10
+
+type attachmentServer struct {
+	repo TaskRepository
+	UnimplementedAttachmentServiceServer
+}
+
+func NewAttachmentServer(repo TaskRepository) *attachmentServer {
+	server := new(attachmentServer)
+
+	server.repo = repo
+
+	return server
+}
+
+func (s *attachmentServer) ListAttachments(req *ListAttachmentsRequest, stream AttachmentService_ListAttachmentsServer) error {
+	attachments, err := s.repo.ListAttachments(stream.Context(), req.GetTaskId())
+	if err != nil {
+		return handleError("grpc.ListAttachments", err)
+	}
+
+	for _, attachment := range attachments {
+		if err := stream.Send(attachmentAtob(attachment)); err != nil {
+			return fmt.Errorf("grpc.ListAttachments: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// Reads the content of an upload from the chunks following its first message.
+type chunkReader struct {
+	stream AttachmentService_UploadAttachmentServer
+	chunk  []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+
+		if req.GetInfo() != nil {
+			return 0, errors.NewExternalError("Only the first message can describe the attachment.")
+		}
+
+		r.chunk = req.GetChunk()
+	}
+
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+
+	return n, nil
+}
+
+func (s *attachmentServer) UploadAttachment(stream AttachmentService_UploadAttachmentServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return fmt.Errorf("grpc.UploadAttachment: %v", err)
+	}
+
+	info := req.GetInfo()
+	if info == nil {
+		return handleError("grpc.UploadAttachment", errors.NewExternalError("The first message must describe the attachment."))
+	}
+
+	attachment, err := s.repo.CreateAttachment(stream.Context(), model.Attachment{
+		TaskID:      info.GetTaskId(),
+		Name:        info.GetName(),
+		ContentType: info.GetContentType(),
+	}, &chunkReader{stream: stream})
+	if err != nil {
+		return handleError("grpc.UploadAttachment", err)
+	}
+
+	return stream.SendAndClose(attachmentAtob(attachment))
+}
+
+func (s *attachmentServer) DownloadAttachment(req *AttachmentRequest, stream AttachmentService_DownloadAttachmentServer) error {
+	_, content, err := s.repo.OpenAttachment(stream.Context(), req.GetTaskId(), req.GetId())
+	if err != nil {
+		return handleError("grpc.DownloadAttachment", err)
+	}
+
+	defer content.Close()
+
+	buf := make([]byte, attachmentChunkSize)
+	for {
+		n, err := content.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&AttachmentChunk{Data: buf[:n]}); err != nil {
+				return fmt.Errorf("grpc.DownloadAttachment: %v", err)
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return handleError("grpc.DownloadAttachment", err)
+		}
+	}
+}
+
+func (s *attachmentServer) DeleteAttachment(ctx context.Context, req *AttachmentRequest) (*empty.Empty, error) {
+	err := s.repo.DeleteAttachment(ctx, req.GetTaskId(), req.GetId())
+	if err != nil {
+		return nil, handleError("grpc.DeleteAttachment", err)
+	}
+
+	return &empty.Empty{}, nil
+}
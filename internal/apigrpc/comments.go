@@ -0,0 +1,59 @@
+package apigrpc
+
+import (
+	context "context"
+	"fmt"
+
+	empty "github.com/golang/protobuf/ptypes/empty"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+type commentServer struct {
+	repo TaskRepository
+	UnimplementedCommentServiceServer
+}
+
+func NewCommentServer(repo TaskRepository) *commentServer {
+	server := new(commentServer)
+
+	server.repo = repo
+
+	return server
+}
+
+func (s *commentServer) ListComments(req *ListCommentsRequest, stream CommentService_ListCommentsServer) error {
+	comments, err := s.repo.ListComments(stream.Context(), req.GetTaskId())
+	if err != nil {
+		return handleError("grpc.ListComments", err)
+	}
+
+	for _, comment := range comments {
+		if err := stream.Send(commentAtob(comment)); err != nil {
+			return fmt.Errorf("grpc.ListComments: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *commentServer) CreateComment(ctx context.Context, req *CreateCommentRequest) (*Comment, error) {
+	comment, err := s.repo.CreateComment(ctx, model.Comment{
+		TaskID: req.GetTaskId(),
+		Author: req.GetAuthor(),
+		Body:   req.GetBody(),
+	})
+	if err != nil {
+		return nil, handleError("grpc.CreateComment", err)
+	}
+
+	return commentAtob(comment), nil
+}
+
+func (s *commentServer) DeleteComment(ctx context.Context, req *DeleteCommentRequest) (*empty.Empty, error) {
+	err := s.repo.DeleteComment(ctx, req.GetTaskId(), req.GetId())
+	if err != nil {
+		return nil, handleError("grpc.DeleteComment", err)
+	}
+
+	return &empty.Empty{}, nil
+}
@@ -1,21 +1,239 @@
 package apigrpc
 
 import (
+	"time"
+
 	"github.com/mtbuzato/go-challenge/internal/model"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func taskAtob(task model.Task) *Task {
 	return &Task{
-		Id:        task.ID,
-		Name:      task.Name,
-		Completed: task.Completed,
+		Id:           task.ID,
+		Name:         task.Name,
+		Description:  task.Description,
+		Completed:    task.Completed,
+		Status:       string(task.Status),
+		Position:     task.Position,
+		Priority:     Priority(task.Priority),
+		ProjectId:    idAtob(task.ProjectID),
+		ParentId:     idAtob(task.ParentID),
+		AutoComplete: task.AutoComplete,
+		Recurrence:   task.Recurrence,
+		CreatedAt:    timestamppb.New(task.CreatedAt),
+		UpdatedAt:    timestamppb.New(task.UpdatedAt),
+		CompletedAt:  timeAtob(task.CompletedAt),
+		DueAt:        timeAtob(task.DueAt),
+		Version:      task.Version,
 	}
 }
 
 func taskBtoa(task *Task) model.Task {
 	return model.Task{
-		ID:        task.Id,
-		Name:      task.Name,
-		Completed: task.Completed,
+		ID:           task.Id,
+		Name:         task.Name,
+		Description:  task.Description,
+		Completed:    task.Completed,
+		Status:       model.Status(task.Status),
+		Priority:     model.Priority(task.Priority),
+		ProjectID:    idBtoa(task.ProjectId),
+		ParentID:     idBtoa(task.ParentId),
+		AutoComplete: task.AutoComplete,
+		Recurrence:   task.Recurrence,
+		DueAt:        timeBtoa(task.DueAt),
+		Version:      task.Version,
+	}
+}
+
+func treeAtob(tree model.TaskTree) *TaskTree {
+	subtasks := make([]*TaskTree, len(tree.Subtasks))
+	for i, subtask := range tree.Subtasks {
+		subtasks[i] = treeAtob(subtask)
+	}
+
+	return &TaskTree{
+		Task:     taskAtob(tree.Task),
+		Subtasks: subtasks,
+	}
+}
+
+func tasksAtob(tasks []model.Task) []*Task {
+	converted := make([]*Task, len(tasks))
+	for i, task := range tasks {
+		converted[i] = taskAtob(task)
+	}
+
+	return converted
+}
+
+func dependenciesAtob(dependencies model.TaskDependencies) *TaskDependencies {
+	return &TaskDependencies{
+		BlockedBy: tasksAtob(dependencies.BlockedBy),
+		Blocks:    tasksAtob(dependencies.Blocks),
+	}
+}
+
+func projectAtob(project model.Project) *Project {
+	return &Project{
+		Id:        project.ID,
+		Name:      project.Name,
+		CreatedAt: timestamppb.New(project.CreatedAt),
+		UpdatedAt: timestamppb.New(project.UpdatedAt),
 	}
 }
+
+func commentAtob(comment model.Comment) *Comment {
+	return &Comment{
+		Id:        comment.ID,
+		TaskId:    comment.TaskID,
+		Author:    comment.Author,
+		Body:      comment.Body,
+		CreatedAt: timestamppb.New(comment.CreatedAt),
+		UpdatedAt: timestamppb.New(comment.UpdatedAt),
+	}
+}
+
+func attachmentAtob(attachment model.Attachment) *Attachment {
+	return &Attachment{
+		Id:          attachment.ID,
+		TaskId:      attachment.TaskID,
+		Name:        attachment.Name,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		CreatedAt:   timestamppb.New(attachment.CreatedAt),
+	}
+}
+
+func historyAtob(history []model.HistoryEntry) *TaskHistory {
+	entries := make([]*HistoryEntry, len(history))
+	for i, entry := range history {
+		changes := make([]*FieldChange, len(entry.Changes))
+		for j, change := range entry.Changes {
+			changes[j] = &FieldChange{
+				Field: change.Field,
+				From:  string(change.From),
+				To:    string(change.To),
+			}
+		}
+
+		entries[i] = &HistoryEntry{
+			Id:        entry.ID,
+			TaskId:    entry.TaskID,
+			Action:    string(entry.Action),
+			Actor:     entry.Actor,
+			Changes:   changes,
+			CreatedAt: timestamppb.New(entry.CreatedAt),
+		}
+	}
+
+	return &TaskHistory{Entries: entries}
+}
+
+func batchBtoa(req *BatchTasksRequest) []model.BatchOperation {
+	operations := make([]model.BatchOperation, len(req.GetOperations()))
+	for i, operation := range req.GetOperations() {
+		task := operation.GetTask()
+		if task == nil {
+			task = &Task{}
+		}
+
+		operations[i] = model.BatchOperation{
+			Action: model.BatchAction(operation.GetAction()),
+			ID:     operation.GetId(),
+			Task:   taskBtoa(task),
+			Patch: model.TaskPatch{
+				Fields: operation.GetUpdateMask().GetPaths(),
+				Task:   taskBtoa(task),
+			},
+		}
+	}
+
+	return operations
+}
+
+func batchAtob(response model.BatchResponse) *BatchTasksResponse {
+	results := make([]*BatchResult, len(response.Results))
+	for i, result := range response.Results {
+		results[i] = &BatchResult{
+			Id:    result.ID,
+			Error: result.Error,
+		}
+	}
+
+	return &BatchTasksResponse{Applied: response.Applied, Results: results}
+}
+
+func tagAtob(tag model.Tag) *Tag {
+	return &Tag{
+		Id:   tag.ID,
+		Name: tag.Name,
+	}
+}
+
+// Converts an optional ID, which is left empty in messages when absent.
+func idAtob(id *string) string {
+	if id == nil {
+		return ""
+	}
+
+	return *id
+}
+
+func idBtoa(id string) *string {
+	if id == "" {
+		return nil
+	}
+
+	return &id
+}
+
+func timeAtob(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+
+	return timestamppb.New(*t)
+}
+
+func timeBtoa(t *timestamppb.Timestamp) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	converted := t.AsTime()
+	return &converted
+}
+
+func queryBtoa(req *QueryTasksRequest) model.TaskQuery {
+	query := model.TaskQuery{
+		Name:          req.GetName(),
+		Status:        model.Status(req.GetStatus()),
+		ProjectID:     req.GetProjectId(),
+		CreatedAfter:  timeBtoa(req.GetCreatedAfter()),
+		CreatedBefore: timeBtoa(req.GetCreatedBefore()),
+		UpdatedAfter:  timeBtoa(req.GetUpdatedAfter()),
+		UpdatedBefore: timeBtoa(req.GetUpdatedBefore()),
+		Sort:          req.GetSort(),
+		Overdue:       req.GetOverdue(),
+		DueWithinDays: int(req.GetDueWithinDays()),
+		Tags:          req.GetTags(),
+		MatchAllTags:  req.GetMatchAllTags(),
+		Descending:    req.GetOrder() == QueryTasksRequest_DESC,
+		Page: model.PageRequest{
+			Limit:  int(req.GetPageSize()),
+			Cursor: req.GetPageToken(),
+		},
+	}
+
+	if req.GetCompleted() != nil {
+		completed := req.GetCompleted().GetValue()
+		query.Completed = &completed
+	}
+
+	if req.GetBlocked() != nil {
+		blocked := req.GetBlocked().GetValue()
+		query.Blocked = &blocked
+	}
+
+	return query
+}
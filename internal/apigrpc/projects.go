@@ -0,0 +1,78 @@
+package apigrpc
+
+import (
+	context "context"
+	"fmt"
+
+	empty "github.com/golang/protobuf/ptypes/empty"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+type projectServer struct {
+	repo TaskRepository
+	UnimplementedProjectServiceServer
+}
+
+func NewProjectServer(repo TaskRepository) *projectServer {
+	server := new(projectServer)
+
+	server.repo = repo
+
+	return server
+}
+
+func (s *projectServer) ListProjects(_ *empty.Empty, stream ProjectService_ListProjectsServer) error {
+	projects, err := s.repo.ListProjects(stream.Context())
+	if err != nil {
+		return handleError("grpc.ListProjects", err)
+	}
+
+	for _, project := range projects {
+		if err := stream.Send(projectAtob(project)); err != nil {
+			return fmt.Errorf("grpc.ListProjects: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *projectServer) GetProject(ctx context.Context, req *GetProjectRequest) (*Project, error) {
+	project, err := s.repo.GetProject(ctx, req.GetId())
+	if err != nil {
+		return nil, handleError("grpc.GetProject", err)
+	}
+
+	return projectAtob(project), nil
+}
+
+func (s *projectServer) CreateProject(ctx context.Context, req *CreateProjectRequest) (*Project, error) {
+	project, err := s.repo.CreateProject(ctx, model.Project{Name: req.GetName()})
+	if err != nil {
+		return nil, handleError("grpc.CreateProject", err)
+	}
+
+	return projectAtob(project), nil
+}
+
+func (s *projectServer) UpdateProject(ctx context.Context, project *Project) (*Project, error) {
+	err := s.repo.UpdateProject(ctx, model.Project{ID: project.GetId(), Name: project.GetName()})
+	if err != nil {
+		return nil, handleError("grpc.UpdateProject", err)
+	}
+
+	updated, err := s.repo.GetProject(ctx, project.GetId())
+	if err != nil {
+		return nil, handleError("grpc.UpdateProject", err)
+	}
+
+	return projectAtob(updated), nil
+}
+
+func (s *projectServer) DeleteProject(ctx context.Context, req *DeleteProjectRequest) (*empty.Empty, error) {
+	err := s.repo.DeleteProject(ctx, req.GetId())
+	if err != nil {
+		return nil, handleError("grpc.DeleteProject", err)
+	}
+
+	return &empty.Empty{}, nil
+}
@@ -0,0 +1,103 @@
+package blob
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Keeps the contents of files under opaque keys. Implementations must be safe
+// for concurrent use.
+type Store interface {
+	// Stores the given content under the key, replacing any previous content,
+	// and returns how many bytes were written.
+	Put(key string, content io.Reader) (int64, error)
+	// Opens the content stored under the key for reading.
+	Open(key string) (io.ReadCloser, error)
+	// Removes the content stored under the key. Removing a missing key is not
+	// an error.
+	Delete(key string) error
+}
+
+// Store keeping each blob as a file in a directory of the local filesystem.
+type LocalStore struct {
+	dir string
+}
+
+// Creates a store in the given directory, creating the directory if needed.
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("Failed to create blob directory: %w", err)
+	}
+
+	return &LocalStore{dir}, nil
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, ".") || strings.ContainsAny(key, `/\`) {
+		return "", fmt.Errorf("Invalid blob key %q.", key)
+	}
+
+	return filepath.Join(s.dir, key), nil
+}
+
+// Writes the content to a temporary file first, so readers never see a blob
+// that is only partially written.
+func (s *LocalStore) Put(key string, content io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	file, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("Failed to create blob: %w", err)
+	}
+
+	defer os.Remove(file.Name())
+
+	written, err := io.Copy(file, content)
+	if err != nil {
+		file.Close()
+		return 0, fmt.Errorf("Failed to write blob: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return 0, fmt.Errorf("Failed to write blob: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), path); err != nil {
+		return 0, fmt.Errorf("Failed to write blob: %w", err)
+	}
+
+	return written, nil
+}
+
+func (s *LocalStore) Open(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open blob: %w", err)
+	}
+
+	return file, nil
+}
+
+func (s *LocalStore) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Failed to delete blob: %w", err)
+	}
+
+	return nil
+}
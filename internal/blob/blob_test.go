@@ -0,0 +1,62 @@
+package blob
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStore(t *testing.T) {
+	assert := assert.New(t)
+
+	store, err := NewLocalStore(t.TempDir())
+	assert.NoError(err)
+
+	written, err := store.Put("key", strings.NewReader("first"))
+	assert.NoError(err)
+	assert.Equal(int64(5), written)
+
+	written, err = store.Put("key", strings.NewReader("second"))
+	assert.NoError(err)
+	assert.Equal(int64(6), written)
+
+	content, err := store.Open("key")
+	assert.NoError(err)
+
+	data, err := io.ReadAll(content)
+	assert.NoError(err)
+	assert.NoError(content.Close())
+	assert.Equal("second", string(data))
+
+	assert.NoError(store.Delete("key"))
+	assert.NoError(store.Delete("key"))
+
+	_, err = store.Open("key")
+	assert.ErrorIs(err, os.ErrNotExist)
+
+	entries, err := os.ReadDir(store.dir)
+	assert.NoError(err)
+	assert.Empty(entries)
+}
+
+func TestLocalStoreInvalidKey(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+
+	for _, key := range []string{"", ".hidden", "../escape", `dir\key`} {
+		t.Run(key, func(t *testing.T) {
+			assert := assert.New(t)
+
+			_, err := store.Put(key, strings.NewReader("content"))
+			assert.Error(err)
+
+			_, err = store.Open(key)
+			assert.Error(err)
+
+			assert.Error(store.Delete(key))
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package errors
 
+import "net/http"
+
 type ExternalError struct {
 	msg            string
 	httpStatusCode int
@@ -52,3 +54,13 @@ func NewHTTPError(message string, code int) error {
 		httpStatusCode: code,
 	}
 }
+
+// Creates an error for a change that conflicts with the current state of a
+// resource, such as an update based on an outdated version of it.
+func NewConflictError(message string) error {
+	return NewHTTPError(message, http.StatusConflict)
+}
+
+func IsConflict(err error) bool {
+	return IsHTTP(err) && GetHTTPStatusCode(err) == http.StatusConflict
+}
@@ -30,3 +30,32 @@ func TestIsExternal(t *testing.T) {
 		})
 	}
 }
+
+func TestIsConflict(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		conflict bool
+	}{
+		"Conflict error": {
+			err:      NewConflictError("Conflict."),
+			conflict: true,
+		},
+		"Other external error": {
+			err:      NewExternalError("Invalid."),
+			conflict: false,
+		},
+		"Non-external error": {
+			err:      nil,
+			conflict: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			conflict := IsConflict(test.err)
+			assert.Equal(conflict, test.conflict)
+		})
+	}
+}
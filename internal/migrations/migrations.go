@@ -0,0 +1,357 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Scripts named "<version>_<name>.up.sql" and "<version>_<name>.down.sql",
+// where the version is a positive number. Versions are applied in ascending
+// order and must never be renumbered once released. Each script holds a single
+// statement, as the driver does not accept several at once. A migration without
+// a down script cannot be reverted.
+//
+//go:embed sql/*.sql
+var scripts embed.FS
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// A migration along with when it was applied to the database, if it was.
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Applies and reverts migrations, keeping track of the applied versions in the
+// schema_migrations table.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// Creates a migrator for the migrations embedded in the binary.
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	sub, err := fs.Sub(scripts, "sql")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open migrations: %w", err)
+	}
+
+	return newMigrator(db, sub)
+}
+
+func newMigrator(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Reads the migrations in the given filesystem, sorted by version. Every
+// migration must have an up script.
+func load(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list migrations: %w", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, file := range files {
+		base := strings.TrimSuffix(path.Base(file), ".sql")
+		direction := path.Ext(base)
+		base = strings.TrimSuffix(base, direction)
+
+		split := strings.SplitN(base, "_", 2)
+		version, err := strconv.ParseInt(split[0], 10, 64)
+		if err != nil || version <= 0 || len(split) != 2 || (direction != ".up" && direction != ".down") {
+			return nil, fmt.Errorf("Invalid migration file name: %s.", file)
+		}
+
+		script, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read migration: %w", err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: split[1]}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != split[1] {
+			return nil, fmt.Errorf("Migration %d has more than one name.", version)
+		}
+
+		if direction == ".up" {
+			migration.Up = string(script)
+		} else {
+			migration.Down = string(script)
+		}
+	}
+
+	migrations := []Migration{}
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("Migration %d must have an up script.", migration.Version)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Returns the latest version known to the migrator, or zero when there are no
+// migrations.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Lists every migration along with whether it was applied.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i].Migration = migration
+
+		if appliedAt, ok := applied[migration.Version]; ok {
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+
+	return statuses, nil
+}
+
+// Applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.To(ctx, m.Latest())
+}
+
+// Reverts the latest applied migration. Reverting without any applied
+// migration does nothing, and reverting one without a down script fails.
+func (m *Migrator) Down(ctx context.Context) error {
+	if err := m.createTable(ctx); err != nil {
+		return err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		if _, ok := applied[m.migrations[i].Version]; ok {
+			return m.revert(ctx, m.migrations[i])
+		}
+	}
+
+	return nil
+}
+
+// Applies or reverts migrations until the database is at the given version.
+// Migrations up to it that are pending are applied in ascending order, and
+// applied ones after it are reverted in descending order. Nothing is reverted
+// when one of them has no down script, which is the case of the first
+// migration: it adopts the tasks table of databases created before migrations,
+// so reverting it could drop data it did not create. Version zero is therefore
+// only reachable from a database without any applied migration.
+func (m *Migrator) To(ctx context.Context, version int64) error {
+	if version != 0 && m.find(version) < 0 {
+		return fmt.Errorf("Unknown migration version: %d.", version)
+	}
+
+	if err := m.createTable(ctx); err != nil {
+		return err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok && migration.Version > version && migration.Down == "" {
+			return irreversibleError(migration)
+		}
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; ok && migration.Version > version {
+			if err := m.revert(ctx, migration); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok && migration.Version <= version {
+			if err := m.apply(ctx, migration); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Checks that every migration was applied and that the database was not
+// migrated by a newer binary, so that servers do not start against a schema
+// they do not match.
+func (m *Migrator) Check(ctx context.Context) error {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	for version := range applied {
+		if m.find(version) < 0 {
+			return fmt.Errorf("Database has unknown migration %d applied, it was migrated by a newer version.", version)
+		}
+	}
+
+	pending := 0
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending++
+		}
+	}
+
+	if pending > 0 {
+		return fmt.Errorf("Database has %d pending migrations, run \"cli migrate up\" to apply them.", pending)
+	}
+
+	return nil
+}
+
+func (m *Migrator) find(version int64) int {
+	for i, migration := range m.migrations {
+		if migration.Version == version {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Creates the table tracking the applied versions if it does not exist yet.
+func (m *Migrator) createTable(ctx context.Context) error {
+	if _, err := m.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at DATETIME NOT NULL)"); err != nil {
+		return fmt.Errorf("Failed to create migrations table: %w", err)
+	}
+
+	return nil
+}
+
+// Returns when each applied version was applied. A database without the
+// tracking table has none applied, and is left untouched so that checking it
+// never changes its schema.
+func (m *Migrator) applied(ctx context.Context) (map[int64]time.Time, error) {
+	applied := map[int64]time.Time{}
+
+	var tables int
+	if err := m.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = 'schema_migrations'").Scan(&tables); err != nil {
+		return nil, fmt.Errorf("Failed to query migrations table: %w", err)
+	}
+
+	if tables == 0 {
+		return applied, nil
+	}
+
+	rows, err := m.db.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query migrations: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("Failed to scan migration: %w", err)
+		}
+
+		applied[version] = appliedAt
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to scan migration: %w", err)
+	}
+
+	return applied, nil
+}
+
+// MySQL commits schema changes implicitly, so the statements of a migration
+// are not run in a transaction. A migration failing halfway is not recorded and
+// may need to be cleaned up by hand.
+func (m *Migrator) apply(ctx context.Context, migration Migration) error {
+	if err := m.exec(ctx, migration, migration.Up); err != nil {
+		return err
+	}
+
+	if _, err := m.db.ExecContext(ctx, "INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)", migration.Version, migration.Name, time.Now().UTC().Truncate(time.Second)); err != nil {
+		return fmt.Errorf("Failed to record migration %d: %w", migration.Version, err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) revert(ctx context.Context, migration Migration) error {
+	if migration.Down == "" {
+		return irreversibleError(migration)
+	}
+
+	if err := m.exec(ctx, migration, migration.Down); err != nil {
+		return err
+	}
+
+	if _, err := m.db.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = ?", migration.Version); err != nil {
+		return fmt.Errorf("Failed to record migration %d: %w", migration.Version, err)
+	}
+
+	return nil
+}
+
+func irreversibleError(migration Migration) error {
+	return fmt.Errorf("Migration %d cannot be reverted.", migration.Version)
+}
+
+// Runs the single statement of the given script as a whole, so that semicolons
+// in its strings or comments are left alone.
+func (m *Migrator) exec(ctx context.Context, migration Migration, script string) error {
+	statement := strings.TrimSuffix(strings.TrimSpace(script), ";")
+
+	if _, err := m.db.ExecContext(ctx, statement); err != nil {
+		return fmt.Errorf("Failed to run migration %d: %w", migration.Version, err)
+	}
+
+	return nil
+}
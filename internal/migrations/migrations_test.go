@@ -0,0 +1,423 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mtbuzato/go-challenge/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var testScripts = fstest.MapFS{
+	"0001_create_tasks.up.sql":      {Data: []byte("CREATE TABLE tasks (id VARCHAR(25));\n")},
+	"0002_create_tags.up.sql":       {Data: []byte("CREATE TABLE tags (id VARCHAR(25) COMMENT 'Tags; labels');\n")},
+	"0002_create_tags.down.sql":     {Data: []byte("DROP TABLE tags;\n")},
+	"0003_create_projects.up.sql":   {Data: []byte("CREATE TABLE projects (id VARCHAR(25));\n")},
+	"0003_create_projects.down.sql": {Data: []byte("DROP TABLE projects;\n")},
+}
+
+func beforeAll(t *testing.T) (*assert.Assertions, *sql.DB, sqlmock.Sqlmock, *Migrator) {
+	assert := assert.New(t)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error opening stub database connection: %s", err)
+	}
+
+	migrator, err := newMigrator(db, testScripts)
+	if err != nil {
+		t.Fatalf("Error loading migrations: %s", err)
+	}
+
+	return assert, db, mock, migrator
+}
+
+func expectCreatedTable(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectTable(mock sqlmock.Sqlmock, exists bool) {
+	tables := 0
+	if exists {
+		tables = 1
+	}
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM information_schema.tables WHERE table_schema = DATABASE\\(\\) AND table_name = 'schema_migrations'").
+		WillReturnRows(mock.NewRows([]string{"count"}).AddRow(tables))
+}
+
+func expectApplied(mock sqlmock.Sqlmock, versions ...int64) {
+	expectTable(mock, true)
+
+	rows := mock.NewRows([]string{"version", "applied_at"})
+	for _, version := range versions {
+		rows.AddRow(version, time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC))
+	}
+
+	mock.ExpectQuery("SELECT version, applied_at FROM schema_migrations").
+		WillReturnRows(rows)
+}
+
+func expectMigrated(mock sqlmock.Sqlmock, version int64, name string, statements ...string) {
+	for _, statement := range statements {
+		mock.ExpectExec(statement).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	mock.ExpectExec("INSERT INTO schema_migrations").
+		WithArgs(version, name, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func expectReverted(mock sqlmock.Sqlmock, version int64, statements ...string) {
+	for _, statement := range statements {
+		mock.ExpectExec(statement).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	mock.ExpectExec("DELETE FROM schema_migrations WHERE version = \\?").
+		WithArgs(version).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func TestLoad(t *testing.T) {
+	tests := map[string]struct {
+		fsys        fstest.MapFS
+		expected    []int64
+		shouldError bool
+	}{
+		"sorted_by_version": {
+			fsys:     testScripts,
+			expected: []int64{1, 2, 3},
+		},
+		"embedded": {
+			fsys:     nil,
+			expected: []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26},
+		},
+		"missing_down_script": {
+			fsys: fstest.MapFS{
+				"0001_create_tasks.up.sql": {Data: []byte("CREATE TABLE tasks (id VARCHAR(25));")},
+			},
+			expected: []int64{1},
+		},
+		"missing_up_script": {
+			fsys: fstest.MapFS{
+				"0001_create_tasks.down.sql": {Data: []byte("DROP TABLE tasks;")},
+			},
+			shouldError: true,
+		},
+		"invalid_version": {
+			fsys: fstest.MapFS{
+				"first_create_tasks.up.sql": {Data: []byte("CREATE TABLE tasks (id VARCHAR(25));")},
+			},
+			shouldError: true,
+		},
+		"invalid_direction": {
+			fsys: fstest.MapFS{
+				"0001_create_tasks.sql": {Data: []byte("CREATE TABLE tasks (id VARCHAR(25));")},
+			},
+			shouldError: true,
+		},
+		"conflicting_names": {
+			fsys: fstest.MapFS{
+				"0001_create_tasks.up.sql":  {Data: []byte("CREATE TABLE tasks (id VARCHAR(25));")},
+				"0001_create_tags.down.sql": {Data: []byte("DROP TABLE tags;")},
+			},
+			shouldError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var migrator *Migrator
+			var err error
+			if test.fsys == nil {
+				migrator, err = NewMigrator(nil)
+			} else {
+				migrator, err = newMigrator(nil, test.fsys)
+			}
+
+			if test.shouldError {
+				assert.Error(err)
+				return
+			}
+
+			assert.NoError(err)
+
+			versions := []int64{}
+			for _, migration := range migrator.migrations {
+				versions = append(versions, migration.Version)
+				assert.NotEmpty(migration.Up)
+
+				// Only the first migration, which adopts an existing tasks table,
+				// cannot be reverted.
+				if migration.Version > 1 {
+					assert.NotEmpty(migration.Down)
+				}
+			}
+			assert.Equal(test.expected, versions)
+		})
+	}
+}
+
+func TestUpFromBaseline(t *testing.T) {
+	assert := assert.New(t)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error opening stub database connection: %s", err)
+	}
+
+	defer db.Close()
+
+	migrator, err := NewMigrator(db)
+	assert.NoError(err)
+
+	// A database created before migrations has the baseline tasks table with
+	// existing rows. Their positions are filled in right after the column is
+	// added, before tasks can be reordered.
+	expectCreatedTable(mock)
+	expectApplied(mock)
+
+	names := []string{}
+	for _, migration := range migrator.migrations {
+		statement := strings.TrimSuffix(strings.TrimSpace(migration.Up), ";")
+		expectMigrated(mock, migration.Version, migration.Name, "^"+regexp.QuoteMeta(statement)+"$")
+		names = append(names, migration.Name)
+	}
+
+	assert.NoError(migrator.Up(context.Background()))
+	assert.NoError(mock.ExpectationsWereMet())
+
+	assert.Equal("create_tasks", names[0])
+	assert.Contains(migrator.migrations[0].Up, "CREATE TABLE IF NOT EXISTS tasks")
+	assert.Equal([]string{"add_task_position", "set_task_positions"}, names[19:21])
+	assert.Contains(migrator.migrations[20].Up, "ROW_NUMBER() OVER (ORDER BY id)")
+	assert.Contains(migrator.migrations[20].Up, "CONCAT('V', LPAD(numbered.number, 10, '0'), 'V')")
+}
+
+func TestBaselinePositions(t *testing.T) {
+	assert := assert.New(t)
+
+	// Positions given to existing tasks by the set_task_positions migration.
+	positions := []string{}
+	for number := 1; number <= 1000; number++ {
+		positions = append(positions, fmt.Sprintf("V%010dV", number))
+	}
+
+	for i := 1; i < len(positions); i++ {
+		assert.Less(positions[i-1], positions[i])
+
+		between, err := model.PositionBetween(positions[i-1], positions[i])
+		assert.NoError(err)
+		assert.Less(positions[i-1], between)
+		assert.Less(between, positions[i])
+	}
+
+	first, err := model.PositionBetween("", positions[0])
+	assert.NoError(err)
+	assert.Less(first, positions[0])
+
+	last, err := model.PositionBetween(positions[len(positions)-1], "")
+	assert.NoError(err)
+	assert.Less(positions[len(positions)-1], last)
+}
+
+func TestStatus(t *testing.T) {
+	assert, db, mock, migrator := beforeAll(t)
+	defer db.Close()
+
+	expectApplied(mock, 1)
+
+	statuses, err := migrator.Status(context.Background())
+	assert.NoError(err)
+	assert.Len(statuses, 3)
+	assert.Equal(int64(1), statuses[0].Version)
+	assert.Equal("create_tasks", statuses[0].Name)
+	assert.NotNil(statuses[0].AppliedAt)
+	assert.Nil(statuses[1].AppliedAt)
+	assert.Nil(statuses[2].AppliedAt)
+
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestMigrate(t *testing.T) {
+	tests := map[string]struct {
+		migrate     func(m *Migrator) error
+		shouldError bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"up_applies_pending": {
+			migrate: func(m *Migrator) error {
+				return m.Up(context.Background())
+			},
+			sql: func(mock sqlmock.Sqlmock) {
+				expectCreatedTable(mock)
+				expectApplied(mock, 1)
+				expectMigrated(mock, 2, "create_tags", "CREATE TABLE tags \\(id VARCHAR\\(25\\) COMMENT 'Tags; labels'\\)$")
+				expectMigrated(mock, 3, "create_projects", "CREATE TABLE projects")
+			},
+		},
+		"up_without_pending": {
+			migrate: func(m *Migrator) error {
+				return m.Up(context.Background())
+			},
+			sql: func(mock sqlmock.Sqlmock) {
+				expectCreatedTable(mock)
+				expectApplied(mock, 1, 2, 3)
+			},
+		},
+		"down_reverts_latest": {
+			migrate: func(m *Migrator) error {
+				return m.Down(context.Background())
+			},
+			sql: func(mock sqlmock.Sqlmock) {
+				expectCreatedTable(mock)
+				expectApplied(mock, 1, 2)
+				expectReverted(mock, 2, "DROP TABLE tags")
+			},
+		},
+		"down_without_applied": {
+			migrate: func(m *Migrator) error {
+				return m.Down(context.Background())
+			},
+			sql: func(mock sqlmock.Sqlmock) {
+				expectCreatedTable(mock)
+				expectApplied(mock)
+			},
+		},
+		"to_older_version": {
+			migrate: func(m *Migrator) error {
+				return m.To(context.Background(), 1)
+			},
+			sql: func(mock sqlmock.Sqlmock) {
+				expectCreatedTable(mock)
+				expectApplied(mock, 1, 2, 3)
+				expectReverted(mock, 3, "DROP TABLE projects")
+				expectReverted(mock, 2, "DROP TABLE tags")
+			},
+		},
+		"to_zero_is_irreversible": {
+			migrate: func(m *Migrator) error {
+				return m.To(context.Background(), 0)
+			},
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				// Nothing is reverted, not even the migrations that could be.
+				expectCreatedTable(mock)
+				expectApplied(mock, 1, 2)
+			},
+		},
+		"to_zero_without_applied": {
+			migrate: func(m *Migrator) error {
+				return m.To(context.Background(), 0)
+			},
+			sql: func(mock sqlmock.Sqlmock) {
+				expectCreatedTable(mock)
+				expectApplied(mock)
+			},
+		},
+		"down_is_irreversible": {
+			migrate: func(m *Migrator) error {
+				return m.Down(context.Background())
+			},
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectCreatedTable(mock)
+				expectApplied(mock, 1)
+			},
+		},
+		"to_unknown_version": {
+			migrate: func(m *Migrator) error {
+				return m.To(context.Background(), 4)
+			},
+			shouldError: true,
+			sql:         func(mock sqlmock.Sqlmock) {},
+		},
+		"failed_statement_is_not_recorded": {
+			migrate: func(m *Migrator) error {
+				return m.Up(context.Background())
+			},
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectCreatedTable(mock)
+				expectApplied(mock, 1)
+				mock.ExpectExec("CREATE TABLE tags").
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock, migrator := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			err := test.migrate(migrator)
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := map[string]struct {
+		missing bool
+		applied []int64
+		err     string
+	}{
+		"missing_table": {
+			missing: true,
+			err:     "Database has 3 pending migrations, run \"cli migrate up\" to apply them.",
+		},
+		"up_to_date": {
+			applied: []int64{1, 2, 3},
+		},
+		"pending": {
+			applied: []int64{1},
+			err:     "Database has 2 pending migrations, run \"cli migrate up\" to apply them.",
+		},
+		"newer_database": {
+			applied: []int64{1, 2, 3, 4},
+			err:     "Database has unknown migration 4 applied, it was migrated by a newer version.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock, migrator := beforeAll(t)
+			defer db.Close()
+
+			// Checking never creates the migrations table.
+			if test.missing {
+				expectTable(mock, false)
+			} else {
+				expectApplied(mock, test.applied...)
+			}
+
+			err := migrator.Check(context.Background())
+			if test.err != "" {
+				assert.EqualError(err, test.err)
+			} else {
+				assert.NoError(err)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
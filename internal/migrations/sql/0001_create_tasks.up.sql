@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS tasks (
+    id        VARCHAR(25)  NOT NULL,
+    name      VARCHAR(128) NOT NULL,
+    completed BOOLEAN      NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (id)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE tasks
+    ADD COLUMN deleted_at DATETIME NULL,
+    ADD INDEX tasks_deleted_at (deleted_at);
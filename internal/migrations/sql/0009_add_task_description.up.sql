@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN description TEXT NOT NULL;
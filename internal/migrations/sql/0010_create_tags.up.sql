@@ -0,0 +1,6 @@
+CREATE TABLE tags (
+    id   VARCHAR(25) NOT NULL,
+    name VARCHAR(32) NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX tags_name (name)
+);
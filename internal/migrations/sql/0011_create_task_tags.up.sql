@@ -0,0 +1,6 @@
+CREATE TABLE task_tags (
+    task_id VARCHAR(25) NOT NULL,
+    tag_id  VARCHAR(25) NOT NULL,
+    PRIMARY KEY (task_id, tag_id),
+    INDEX task_tags_tag_id (tag_id)
+);
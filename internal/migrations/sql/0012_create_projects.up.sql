@@ -0,0 +1,8 @@
+CREATE TABLE projects (
+    id         VARCHAR(25)  NOT NULL,
+    name       VARCHAR(128) NOT NULL,
+    created_at DATETIME     NOT NULL,
+    updated_at DATETIME     NOT NULL,
+    PRIMARY KEY (id),
+    INDEX projects_name (name)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE tasks
+    ADD COLUMN project_id VARCHAR(25) NULL,
+    ADD INDEX tasks_project_id (project_id);
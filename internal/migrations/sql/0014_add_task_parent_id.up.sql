@@ -0,0 +1,3 @@
+ALTER TABLE tasks
+    ADD COLUMN parent_id VARCHAR(25) NULL,
+    ADD INDEX tasks_parent_id (parent_id);
@@ -0,0 +1 @@
+ALTER TABLE tasks DROP COLUMN auto_complete;
@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN auto_complete BOOLEAN NOT NULL DEFAULT FALSE;
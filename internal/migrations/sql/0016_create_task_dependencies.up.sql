@@ -0,0 +1,6 @@
+CREATE TABLE task_dependencies (
+    task_id    VARCHAR(25) NOT NULL,
+    blocker_id VARCHAR(25) NOT NULL,
+    PRIMARY KEY (task_id, blocker_id),
+    INDEX task_dependencies_blocker_id (blocker_id)
+);
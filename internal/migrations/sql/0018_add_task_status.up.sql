@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN status VARCHAR(32) NOT NULL DEFAULT 'todo';
@@ -0,0 +1 @@
+UPDATE tasks SET status = 'done' WHERE completed;
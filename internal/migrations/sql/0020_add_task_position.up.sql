@@ -0,0 +1,3 @@
+ALTER TABLE tasks
+    ADD COLUMN position VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin NOT NULL,
+    ADD INDEX tasks_position (position);
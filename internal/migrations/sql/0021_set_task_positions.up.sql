@@ -0,0 +1,3 @@
+UPDATE tasks
+    JOIN (SELECT id, ROW_NUMBER() OVER (ORDER BY id) AS number FROM tasks WHERE position = '') numbered ON numbered.id = tasks.id
+SET tasks.position = CONCAT('V', LPAD(numbered.number, 10, '0'), 'V');
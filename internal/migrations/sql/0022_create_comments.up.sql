@@ -0,0 +1,10 @@
+CREATE TABLE comments (
+    id         VARCHAR(25)  NOT NULL,
+    task_id    VARCHAR(25)  NOT NULL,
+    author     VARCHAR(128) NOT NULL,
+    body       TEXT         NOT NULL,
+    created_at DATETIME     NOT NULL,
+    updated_at DATETIME     NOT NULL,
+    PRIMARY KEY (id),
+    INDEX comments_task_id (task_id, created_at)
+);
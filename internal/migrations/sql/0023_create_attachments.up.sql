@@ -0,0 +1,10 @@
+CREATE TABLE attachments (
+    id           VARCHAR(25)  NOT NULL,
+    task_id      VARCHAR(25)  NOT NULL,
+    name         VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size         BIGINT       NOT NULL,
+    created_at   DATETIME     NOT NULL,
+    PRIMARY KEY (id),
+    INDEX attachments_task_id (task_id, created_at)
+);
@@ -0,0 +1,10 @@
+CREATE TABLE task_history (
+    id         VARCHAR(25)  NOT NULL,
+    task_id    VARCHAR(25)  NOT NULL,
+    action     VARCHAR(32)  NOT NULL,
+    actor      VARCHAR(128) NOT NULL,
+    changes    MEDIUMTEXT   NOT NULL,
+    created_at DATETIME     NOT NULL,
+    PRIMARY KEY (id),
+    INDEX task_history_task_id (task_id, created_at)
+);
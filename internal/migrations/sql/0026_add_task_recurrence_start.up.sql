@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN recurrence_start DATETIME NULL;
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/lucsky/cuid"
+	"github.com/mtbuzato/go-challenge/internal/errors"
+)
+
+// Maximum size of an attachment in bytes.
+const MaxAttachmentSize = 10 << 20
+
+// Media types of the files that can be attached to tasks.
+var AttachmentTypes = []string{
+	"application/json",
+	"application/pdf",
+	"application/zip",
+	"image/gif",
+	"image/jpeg",
+	"image/png",
+	"image/webp",
+	"text/csv",
+	"text/plain",
+}
+
+// A file attached to a task. Only its metadata is kept in the database, the
+// content lives in a blob store under the attachment ID.
+type Attachment struct {
+	ID          string    `json:"id" gorm:"primaryKey"`
+	TaskID      string    `json:"task_id"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func ValidateAttachmentID(id string) error {
+	if cuid.IsCuid(id) != nil {
+		return errors.NewExternalError("Invalid attachment ID.")
+	}
+
+	return nil
+}
+
+func ValidateAttachmentName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, "/\\\"\r\n") {
+		return errors.NewExternalError("Invalid attachment name.")
+	}
+
+	if len(name) > 255 {
+		return errors.NewExternalError("Attachment name is too long.")
+	}
+
+	return nil
+}
+
+func ValidateAttachmentType(contentType string) error {
+	for _, allowed := range AttachmentTypes {
+		if contentType == allowed {
+			return nil
+		}
+	}
+
+	return errors.NewExternalError("Attachment type is not allowed.")
+}
+
+func ValidateAttachmentSize(size int64) error {
+	if size > MaxAttachmentSize {
+		return errors.NewExternalError("Attachment is too large.")
+	}
+
+	return nil
+}
+
+// Prepares an attachment to be created, assigning it a new ID, setting its
+// creation time to the current time and dropping any parameters from its
+// content type.
+func (a *Attachment) Init() {
+	a.ID = cuid.New()
+	a.CreatedAt = Now()
+
+	if mediaType, _, err := mime.ParseMediaType(a.ContentType); err == nil {
+		a.ContentType = mediaType
+	}
+}
+
+func (a *Attachment) Validate() error {
+	err := ValidateAttachmentID(a.ID)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateID(a.TaskID)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateAttachmentName(a.Name)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateAttachmentType(a.ContentType)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateAttachmentSize(a.Size)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
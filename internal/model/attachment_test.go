@@ -0,0 +1,76 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucsky/cuid"
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttachmentValidate(t *testing.T) {
+	taskID := cuid.New()
+
+	tests := map[string]struct {
+		attachment Attachment
+		err        string
+	}{
+		"Valid attachment": {
+			attachment: Attachment{ID: cuid.New(), TaskID: taskID, Name: "screenshot.png", ContentType: "image/png", Size: 1024},
+		},
+		"Invalid attachment ID": {
+			attachment: Attachment{ID: "1", TaskID: taskID, Name: "screenshot.png", ContentType: "image/png"},
+			err:        "Invalid attachment ID.",
+		},
+		"Invalid task ID": {
+			attachment: Attachment{ID: cuid.New(), TaskID: "1", Name: "screenshot.png", ContentType: "image/png"},
+			err:        "Invalid task ID.",
+		},
+		"Empty attachment name": {
+			attachment: Attachment{ID: cuid.New(), TaskID: taskID, Name: "", ContentType: "image/png"},
+			err:        "Invalid attachment name.",
+		},
+		"Attachment name with a path": {
+			attachment: Attachment{ID: cuid.New(), TaskID: taskID, Name: "../screenshot.png", ContentType: "image/png"},
+			err:        "Invalid attachment name.",
+		},
+		"Attachment name too long": {
+			attachment: Attachment{ID: cuid.New(), TaskID: taskID, Name: strings.Repeat("a", 256), ContentType: "image/png"},
+			err:        "Attachment name is too long.",
+		},
+		"Attachment type not allowed": {
+			attachment: Attachment{ID: cuid.New(), TaskID: taskID, Name: "script.sh", ContentType: "application/x-sh"},
+			err:        "Attachment type is not allowed.",
+		},
+		"Attachment too large": {
+			attachment: Attachment{ID: cuid.New(), TaskID: taskID, Name: "server.log", ContentType: "text/plain", Size: MaxAttachmentSize + 1},
+			err:        "Attachment is too large.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := test.attachment.Validate()
+			if test.err != "" {
+				assert.Equal(err.Error(), test.err)
+				assert.True(errors.IsExternal(err))
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
+
+func TestAttachmentInit(t *testing.T) {
+	assert := assert.New(t)
+
+	attachment := Attachment{Name: "server.log", ContentType: "Text/Plain; charset=utf-8"}
+	attachment.Init()
+
+	assert.NoError(ValidateAttachmentID(attachment.ID))
+	assert.Equal("text/plain", attachment.ContentType)
+	assert.False(attachment.CreatedAt.IsZero())
+}
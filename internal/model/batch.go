@@ -0,0 +1,97 @@
+package model
+
+import (
+	"strconv"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+)
+
+// Maximum number of operations in a single batch.
+const MaxBatchSize = 500
+
+type BatchAction string
+
+const (
+	BatchCreate BatchAction = "create"
+	BatchUpdate BatchAction = "update"
+	BatchDelete BatchAction = "delete"
+)
+
+// A single change in a batch. Creations use the task, updates apply the patch
+// to the task with the ID, and deletions move the task with the ID to the
+// trash.
+type BatchOperation struct {
+	Action BatchAction
+	ID     string
+	Task   Task
+	Patch  TaskPatch
+}
+
+// The outcome of an operation of a batch: the ID of the task it created or
+// changed, or the reason it failed.
+type BatchResult struct {
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// The outcome of a batch. When it is not applied, because it was atomic and
+// an operation failed, none of its operations were.
+type BatchResponse struct {
+	Applied bool          `json:"applied"`
+	Results []BatchResult `json:"results"`
+}
+
+// Returned by an atomic batch that was not applied, so that the transaction it
+// ran in is rolled back along with any transaction enclosing it. It carries the
+// response reporting which operations failed.
+type BatchNotAppliedError struct {
+	Response BatchResponse
+}
+
+func (e *BatchNotAppliedError) Error() string {
+	return "Batch was not applied."
+}
+
+// Returns the response carried by an error returned by an atomic batch that
+// was not applied.
+func NotAppliedBatch(err error) (BatchResponse, bool) {
+	notApplied, ok := err.(*BatchNotAppliedError)
+	if !ok {
+		return BatchResponse{}, false
+	}
+
+	return notApplied.Response, true
+}
+
+func ValidateBatch(operations []BatchOperation) error {
+	if len(operations) == 0 {
+		return errors.NewExternalError("Batch is empty.")
+	}
+
+	if len(operations) > MaxBatchSize {
+		return errors.NewExternalError("Batch cannot have more than " + strconv.Itoa(MaxBatchSize) + " operations.")
+	}
+
+	for _, operation := range operations {
+		switch operation.Action {
+		case BatchCreate, BatchUpdate, BatchDelete:
+		default:
+			return errors.NewExternalError("Invalid batch action.")
+		}
+	}
+
+	return nil
+}
+
+// Returns the response to a batch with the given results, which is only
+// applied when it is not atomic or every operation succeeded.
+func NewBatchResponse(results []BatchResult, atomic bool) BatchResponse {
+	applied := true
+	if atomic {
+		for _, result := range results {
+			applied = applied && result.Error == ""
+		}
+	}
+
+	return BatchResponse{Applied: applied, Results: results}
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBatch(t *testing.T) {
+	tests := map[string]struct {
+		operations []BatchOperation
+		err        string
+	}{
+		"Valid batch": {
+			operations: []BatchOperation{
+				{Action: BatchCreate, Task: Task{Name: "Task 1"}},
+				{Action: BatchUpdate, ID: "cl09rb83d000009l13y5n5ur8"},
+				{Action: BatchDelete, ID: "cl09rb83d000009l13y5n5ur9"},
+			},
+		},
+		"Empty batch": {
+			operations: []BatchOperation{},
+			err:        "Batch is empty.",
+		},
+		"Batch that is too large": {
+			operations: make([]BatchOperation, MaxBatchSize+1),
+			err:        "Batch cannot have more than 500 operations.",
+		},
+		"Invalid action": {
+			operations: []BatchOperation{{Action: "archive"}},
+			err:        "Invalid batch action.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := ValidateBatch(test.operations)
+			if test.err != "" {
+				assert.EqualError(err, test.err)
+				assert.True(errors.IsExternal(err))
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
+
+func TestNewBatchResponse(t *testing.T) {
+	assert := assert.New(t)
+	results := []BatchResult{{ID: "1"}, {ID: "2", Error: "Task not found."}}
+
+	assert.True(NewBatchResponse(results, false).Applied)
+	assert.False(NewBatchResponse(results, true).Applied)
+	assert.True(NewBatchResponse(results[:1], true).Applied)
+	assert.Equal(results, NewBatchResponse(results, true).Results)
+}
+
+func TestNotAppliedBatch(t *testing.T) {
+	assert := assert.New(t)
+	response := NewBatchResponse([]BatchResult{{ID: "1", Error: "Task not found."}}, true)
+
+	notApplied, ok := NotAppliedBatch(&BatchNotAppliedError{Response: response})
+	assert.True(ok)
+	assert.Equal(response, notApplied)
+
+	_, ok = NotAppliedBatch(errors.NewExternalError("Batch is empty."))
+	assert.False(ok)
+
+	_, ok = NotAppliedBatch(nil)
+	assert.False(ok)
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lucsky/cuid"
+	"github.com/mtbuzato/go-challenge/internal/errors"
+)
+
+// Maximum size of a comment body in bytes, matching a MySQL TEXT column.
+const MaxCommentLength = 65535
+
+// A note left on a task. Comments are listed in the order they were written.
+type Comment struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	TaskID    string    `json:"task_id"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func ValidateCommentID(id string) error {
+	if cuid.IsCuid(id) != nil {
+		return errors.NewExternalError("Invalid comment ID.")
+	}
+
+	return nil
+}
+
+func ValidateCommentAuthor(author string) error {
+	if author == "" {
+		return errors.NewExternalError("Invalid comment author.")
+	}
+
+	if len(author) > 128 {
+		return errors.NewExternalError("Comment author is too long.")
+	}
+
+	return nil
+}
+
+func ValidateCommentBody(body string) error {
+	if body == "" {
+		return errors.NewExternalError("Invalid comment body.")
+	}
+
+	if len(body) > MaxCommentLength {
+		return errors.NewExternalError("Comment body is too long.")
+	}
+
+	return nil
+}
+
+// Prepares a comment to be created, assigning it a new ID and setting its
+// timestamps to the current time.
+func (c *Comment) Init() {
+	c.ID = cuid.New()
+	c.CreatedAt = Now()
+	c.UpdatedAt = c.CreatedAt
+}
+
+func (c *Comment) Validate() error {
+	err := ValidateCommentID(c.ID)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateID(c.TaskID)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateCommentAuthor(c.Author)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateCommentBody(c.Body)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucsky/cuid"
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentValidate(t *testing.T) {
+	taskID := cuid.New()
+
+	tests := map[string]struct {
+		comment Comment
+		err     string
+	}{
+		"Valid comment": {
+			comment: Comment{ID: cuid.New(), TaskID: taskID, Author: "Alice", Body: "Looks good to me."},
+		},
+		"Invalid comment ID": {
+			comment: Comment{ID: "1", TaskID: taskID, Author: "Alice", Body: "Looks good to me."},
+			err:     "Invalid comment ID.",
+		},
+		"Invalid task ID": {
+			comment: Comment{ID: cuid.New(), TaskID: "1", Author: "Alice", Body: "Looks good to me."},
+			err:     "Invalid task ID.",
+		},
+		"Invalid comment author": {
+			comment: Comment{ID: cuid.New(), TaskID: taskID, Author: "", Body: "Looks good to me."},
+			err:     "Invalid comment author.",
+		},
+		"Comment author too long": {
+			comment: Comment{ID: cuid.New(), TaskID: taskID, Author: strings.Repeat("a", 129), Body: "Looks good to me."},
+			err:     "Comment author is too long.",
+		},
+		"Invalid comment body": {
+			comment: Comment{ID: cuid.New(), TaskID: taskID, Author: "Alice", Body: ""},
+			err:     "Invalid comment body.",
+		},
+		"Comment body too long": {
+			comment: Comment{ID: cuid.New(), TaskID: taskID, Author: "Alice", Body: strings.Repeat("a", MaxCommentLength+1)},
+			err:     "Comment body is too long.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := test.comment.Validate()
+			if test.err != "" {
+				assert.Equal(err.Error(), test.err)
+				assert.True(errors.IsExternal(err))
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"github.com/mtbuzato/go-challenge/internal/errors"
+)
+
+// The tasks a task is blocked by and the tasks it blocks. A task is blocked
+// while any of its blockers is open, that is, neither completed nor in the
+// trash.
+type TaskDependencies struct {
+	BlockedBy []Task `json:"blocked_by"`
+	Blocks    []Task `json:"blocks"`
+}
+
+// Validates that the task with the given ID can be blocked by another task,
+// given every task that the blocker is directly or indirectly blocked by.
+func ValidateDependency(id string, blockerID string, blockerBlockerIDs []string) error {
+	if err := ValidateID(blockerID); err != nil {
+		return errors.NewExternalError("Invalid blocker task ID.")
+	}
+
+	if id == blockerID {
+		return errors.NewExternalError("A task cannot block itself.")
+	}
+
+	for _, blockerBlockerID := range blockerBlockerIDs {
+		if blockerBlockerID == id {
+			return errors.NewExternalError("Task dependencies cannot contain cycles.")
+		}
+	}
+
+	return nil
+}
+
+// Returns the SQL condition matching tasks depending on whether they are
+// blocked and its arguments, or an empty condition when the query does not
+// filter on it.
+func (q *TaskQuery) BlockedCondition() (string, []interface{}) {
+	if q.Blocked == nil {
+		return "", nil
+	}
+
+	operator := "IN"
+	if !*q.Blocked {
+		operator = "NOT IN"
+	}
+
+	return "id " + operator + " (SELECT task_dependencies.task_id FROM task_dependencies JOIN tasks AS blockers ON blockers.id = task_dependencies.blocker_id WHERE blockers.completed = ? AND blockers.deleted_at IS NULL)", []interface{}{false}
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/lucsky/cuid"
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDependency(t *testing.T) {
+	id := cuid.New()
+	blockerID := cuid.New()
+	otherID := cuid.New()
+
+	tests := map[string]struct {
+		blockerID         string
+		blockerBlockerIDs []string
+		err               string
+	}{
+		"Dependency": {
+			blockerID:         blockerID,
+			blockerBlockerIDs: []string{otherID},
+		},
+		"Invalid blocker ID": {
+			blockerID: "invalid",
+			err:       "Invalid blocker task ID.",
+		},
+		"Task that blocks itself": {
+			blockerID: id,
+			err:       "A task cannot block itself.",
+		},
+		"Dependency that creates a cycle": {
+			blockerID:         blockerID,
+			blockerBlockerIDs: []string{otherID, id},
+			err:               "Task dependencies cannot contain cycles.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := ValidateDependency(id, test.blockerID, test.blockerBlockerIDs)
+			if test.err != "" {
+				assert.Equal(err.Error(), test.err)
+				assert.True(errors.IsExternal(err))
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
+
+func TestTaskQueryBlockedCondition(t *testing.T) {
+	blocked := true
+	unblocked := false
+
+	tests := map[string]struct {
+		query     TaskQuery
+		condition string
+		args      []interface{}
+	}{
+		"No blocked filter": {
+			query:     TaskQuery{},
+			condition: "",
+			args:      nil,
+		},
+		"Blocked tasks": {
+			query:     TaskQuery{Blocked: &blocked},
+			condition: "id IN (SELECT task_dependencies.task_id FROM task_dependencies JOIN tasks AS blockers ON blockers.id = task_dependencies.blocker_id WHERE blockers.completed = ? AND blockers.deleted_at IS NULL)",
+			args:      []interface{}{false},
+		},
+		"Unblocked tasks": {
+			query:     TaskQuery{Blocked: &unblocked},
+			condition: "id NOT IN (SELECT task_dependencies.task_id FROM task_dependencies JOIN tasks AS blockers ON blockers.id = task_dependencies.blocker_id WHERE blockers.completed = ? AND blockers.deleted_at IS NULL)",
+			args:      []interface{}{false},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			condition, args := test.query.BlockedCondition()
+			assert.Equal(test.condition, condition)
+			assert.Equal(test.args, args)
+		})
+	}
+}
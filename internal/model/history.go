@@ -0,0 +1,100 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/lucsky/cuid"
+	"github.com/mtbuzato/go-challenge/internal/errors"
+)
+
+// What happened to a task in an entry of its history.
+type HistoryAction string
+
+const (
+	HistoryCreated  HistoryAction = "created"
+	HistoryUpdated  HistoryAction = "updated"
+	HistoryDeleted  HistoryAction = "deleted"
+	HistoryRestored HistoryAction = "restored"
+)
+
+// A change to a single field of a task. Values are JSON encoded, with null
+// standing for a field that is not set.
+type FieldChange struct {
+	Field string          `json:"field"`
+	From  json.RawMessage `json:"from"`
+	To    json.RawMessage `json:"to"`
+}
+
+// A record of a change made to a task, and of who made it.
+type HistoryEntry struct {
+	ID        string        `json:"id"`
+	TaskID    string        `json:"task_id"`
+	Action    HistoryAction `json:"action"`
+	Actor     string        `json:"actor"`
+	Changes   []FieldChange `json:"changes"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// Validates the name of who makes a change. It is supplied by clients and may
+// be empty when they do not identify themselves.
+func ValidateActor(actor string) error {
+	if len(actor) > 128 {
+		return errors.NewExternalError("Actor is too long.")
+	}
+
+	return nil
+}
+
+// Creates a history entry for a change made to a task now.
+func NewHistoryEntry(taskID string, action HistoryAction, actor string, changes []FieldChange) HistoryEntry {
+	if changes == nil {
+		changes = []FieldChange{}
+	}
+
+	return HistoryEntry{
+		ID:        cuid.New(),
+		TaskID:    taskID,
+		Action:    action,
+		Actor:     actor,
+		Changes:   changes,
+		CreatedAt: Now(),
+	}
+}
+
+// Fields of a task tracked in its history, by their JSON names. Timestamps
+// and the manual position are left out.
+var historyFields = []struct {
+	name  string
+	value func(t Task) interface{}
+}{
+	{"name", func(t Task) interface{} { return t.Name }},
+	{"description", func(t Task) interface{} { return t.Description }},
+	{"completed", func(t Task) interface{} { return t.Completed }},
+	{"status", func(t Task) interface{} { return t.Status }},
+	{"priority", func(t Task) interface{} { return t.Priority }},
+	{"project_id", func(t Task) interface{} { return t.ProjectID }},
+	{"parent_id", func(t Task) interface{} { return t.ParentID }},
+	{"auto_complete", func(t Task) interface{} { return t.AutoComplete }},
+	{"recurrence", func(t Task) interface{} { return t.Recurrence }},
+	{"due_at", func(t Task) interface{} { return t.DueAt }},
+}
+
+// Lists the tracked fields that differ between two versions of a task. The
+// changes made by creating a task are its differences from an empty one.
+func DiffTasks(before Task, after Task) []FieldChange {
+	changes := []FieldChange{}
+
+	for _, field := range historyFields {
+		// None of the tracked fields can fail to be encoded.
+		from, _ := json.Marshal(field.value(before))
+		to, _ := json.Marshal(field.value(after))
+
+		if !bytes.Equal(from, to) {
+			changes = append(changes, FieldChange{Field: field.name, From: from, To: to})
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTasks(t *testing.T) {
+	projectID := "cl09rb83d000009l13y5n5pr1"
+	dueAt := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		before   Task
+		after    Task
+		expected []FieldChange
+	}{
+		"No changes": {
+			before:   Task{Name: "Task 1", Position: "V"},
+			after:    Task{Name: "Task 1", Position: "l", UpdatedAt: dueAt},
+			expected: []FieldChange{},
+		},
+		"Renamed and completed": {
+			before: Task{Name: "Task 1", Status: StatusTodo},
+			after:  Task{Name: "Task 2", Completed: true, Status: StatusDone},
+			expected: []FieldChange{
+				{Field: "name", From: json.RawMessage(`"Task 1"`), To: json.RawMessage(`"Task 2"`)},
+				{Field: "completed", From: json.RawMessage(`false`), To: json.RawMessage(`true`)},
+				{Field: "status", From: json.RawMessage(`"todo"`), To: json.RawMessage(`"done"`)},
+			},
+		},
+		"Set project and due date": {
+			before: Task{Name: "Task 1"},
+			after:  Task{Name: "Task 1", ProjectID: &projectID, DueAt: &dueAt},
+			expected: []FieldChange{
+				{Field: "project_id", From: json.RawMessage(`null`), To: json.RawMessage(`"` + projectID + `"`)},
+				{Field: "due_at", From: json.RawMessage(`null`), To: json.RawMessage(`"2022-03-01T12:00:00Z"`)},
+			},
+		},
+		"Created": {
+			before: Task{},
+			after:  Task{Name: "Task 1", Status: StatusTodo, Priority: PriorityHigh},
+			expected: []FieldChange{
+				{Field: "name", From: json.RawMessage(`""`), To: json.RawMessage(`"Task 1"`)},
+				{Field: "status", From: json.RawMessage(`""`), To: json.RawMessage(`"todo"`)},
+				{Field: "priority", From: json.RawMessage(`"none"`), To: json.RawMessage(`"high"`)},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, DiffTasks(test.before, test.after))
+		})
+	}
+}
+
+func TestValidateActor(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(ValidateActor(""))
+	assert.NoError(ValidateActor("alice"))
+	assert.EqualError(ValidateActor(strings.Repeat("a", 129)), "Actor is too long.")
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var (
+	markdown  = goldmark.New(goldmark.WithExtensions(extension.GFM))
+	sanitizer = bluemonday.UGCPolicy()
+)
+
+// Renders a Markdown document to HTML. The output is sanitized, so it is safe
+// to embed in a page even when the source was written by a user.
+func RenderMarkdown(source string) (string, error) {
+	var buf bytes.Buffer
+
+	err := markdown.Convert([]byte(source), &buf)
+	if err != nil {
+		return "", fmt.Errorf("Failed to render markdown: %w", err)
+	}
+
+	return sanitizer.Sanitize(buf.String()), nil
+}
+
+// Fills in the HTML rendering of the task's description.
+func (t *Task) RenderDescription() error {
+	html, err := RenderMarkdown(t.Description)
+	if err != nil {
+		return err
+	}
+
+	t.DescriptionHTML = html
+
+	return nil
+}
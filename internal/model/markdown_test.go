@@ -0,0 +1,41 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := map[string]struct {
+		source   string
+		expected string
+	}{
+		"Empty description": {
+			source:   "",
+			expected: "",
+		},
+		"Formatted description": {
+			source:   "Buy **milk**\n\n- [Store](https://example.com)",
+			expected: "<p>Buy <strong>milk</strong></p>\n<ul>\n<li><a href=\"https://example.com\" rel=\"nofollow\">Store</a></li>\n</ul>\n",
+		},
+		"Description with a script": {
+			source:   "Hello <script>alert(1)</script>",
+			expected: "<p>Hello alert(1)</p>\n",
+		},
+		"Description with a javascript link": {
+			source:   "[Click](javascript:alert(1))",
+			expected: "<p>Click</p>\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			html, err := RenderMarkdown(test.source)
+			assert.NoError(err)
+			assert.Equal(test.expected, html)
+		})
+	}
+}
@@ -1,14 +1,50 @@
 package model
 
 import (
+	"time"
+
 	"github.com/lucsky/cuid"
 	"github.com/mtbuzato/go-challenge/internal/errors"
 )
 
+// Maximum size of a task description in bytes, matching a MySQL TEXT column.
+const MaxDescriptionLength = 65535
+
 type Task struct {
-	ID        string `json:"id" gorm:"primaryKey"`
-	Name      string `json:"name"`
-	Completed bool   `json:"completed"`
+	ID           string     `json:"id" gorm:"primaryKey"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	Completed    bool       `json:"completed"`
+	Status       Status     `json:"status"`
+	Position     string     `json:"position"`
+	Priority     Priority   `json:"priority"`
+	ProjectID    *string    `json:"project_id,omitempty"`
+	ParentID     *string    `json:"parent_id,omitempty"`
+	AutoComplete bool       `json:"auto_complete"`
+	Recurrence   string     `json:"recurrence"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	DueAt        *time.Time `json:"due_at,omitempty"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+
+	// Start of the series of occurrences of a recurring task, from which its
+	// rule is evaluated so that COUNT and UNTIL apply to the whole series.
+	RecurrenceStart *time.Time `json:"recurrence_start,omitempty"`
+
+	// Incremented every time the task is changed, so that updates based on an
+	// outdated copy of it can be rejected.
+	Version int64 `json:"version"`
+
+	// Sanitized HTML rendering of the description. It is never persisted and
+	// is only filled in when a client asks for it.
+	DescriptionHTML string `json:"description_html,omitempty" gorm:"-"`
+}
+
+// Returns the current time the way it is stored by the repositories: in UTC
+// and truncated to seconds.
+func Now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
 }
 
 func ValidateID(id string) error {
@@ -31,6 +67,49 @@ func ValidateName(name string) error {
 	return nil
 }
 
+func ValidateDescription(description string) error {
+	if len(description) > MaxDescriptionLength {
+		return errors.NewExternalError("Task description is too long.")
+	}
+
+	return nil
+}
+
+func ValidateDueAt(dueAt *time.Time) error {
+	if dueAt == nil {
+		return nil
+	}
+
+	if dueAt.Year() < 1970 || dueAt.Year() > 9999 {
+		return errors.NewExternalError("Invalid task due date.")
+	}
+
+	return nil
+}
+
+// Prepares a task to be created, assigning it a new ID and its first version
+// and setting its timestamps to the current time.
+func (t *Task) Init() {
+	t.ID = cuid.New()
+	t.CreatedAt = Now()
+	t.UpdatedAt = t.CreatedAt
+	t.CompletedAt = nil
+	t.DeletedAt = nil
+	t.Version = 1
+
+	t.SyncStatus()
+
+	if t.Completed {
+		completedAt := t.CreatedAt
+		t.CompletedAt = &completedAt
+	}
+
+	if t.DueAt != nil {
+		dueAt := t.DueAt.UTC().Truncate(time.Second)
+		t.DueAt = &dueAt
+	}
+}
+
 func (t *Task) Validate() error {
 	err := ValidateID(t.ID)
 	if err != nil {
@@ -42,5 +121,51 @@ func (t *Task) Validate() error {
 		return err
 	}
 
+	err = ValidateDescription(t.Description)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateDueAt(t.DueAt)
+	if err != nil {
+		return err
+	}
+
+	err = ValidatePriority(t.Priority)
+	if err != nil {
+		return err
+	}
+
+	if t.Status != "" {
+		err = ValidateStatus(t.Status)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = ValidateRecurrence(t.Recurrence)
+	if err != nil {
+		return err
+	}
+
+	if t.ProjectID != nil {
+		err = ValidateProjectID(*t.ProjectID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if t.ParentID != nil {
+		err = ValidateParentID(*t.ParentID)
+		if err != nil {
+			return err
+		}
+
+		err = ValidateHierarchy(t.ID, []string{*t.ParentID})
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -1,7 +1,9 @@
 package model
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/lucsky/cuid"
 	"github.com/mtbuzato/go-challenge/internal/errors"
@@ -74,3 +76,71 @@ func TestValidateName(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateDescription(t *testing.T) {
+	tests := map[string]struct {
+		description string
+		err         string
+	}{
+		"Empty description": {
+			description: "",
+		},
+		"Valid description": {
+			description: strings.Repeat("a", MaxDescriptionLength),
+		},
+		"Description too long": {
+			description: strings.Repeat("a", MaxDescriptionLength+1),
+			err:         "Task description is too long.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := ValidateDescription(test.description)
+			if test.err != "" {
+				assert.Equal(err.Error(), test.err)
+				assert.True(errors.IsExternal(err))
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
+
+func TestValidateDueAt(t *testing.T) {
+	dueAt := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		dueAt    *time.Time
+		err      string
+		external bool
+	}{
+		"No due date": {
+			dueAt: nil,
+		},
+		"Valid due date": {
+			dueAt: &dueAt,
+		},
+		"Invalid due date": {
+			dueAt:    &time.Time{},
+			err:      "Invalid task due date.",
+			external: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := ValidateDueAt(test.dueAt)
+			if test.err != "" {
+				assert.Equal(err.Error(), test.err)
+				assert.True(errors.IsExternal(err))
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
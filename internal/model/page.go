@@ -0,0 +1,133 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 1000
+)
+
+type PageRequest struct {
+	Limit  int
+	Cursor string
+}
+
+type TaskPage struct {
+	Tasks      []Task `json:"tasks"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Points to the last task of a page, identified by the values of the keys the
+// listing is sorted by, the last of which is its ID.
+type Cursor struct {
+	Sort       string
+	Descending bool
+	Values     []interface{}
+}
+
+type encodedCursor struct {
+	Sort       string            `json:"s"`
+	Descending bool              `json:"d,omitempty"`
+	Values     []json.RawMessage `json:"v"`
+}
+
+// Returns the ID of the task the cursor points to.
+func (c *Cursor) ID() string {
+	return c.Values[len(c.Values)-1].(string)
+}
+
+// Encodes the position of the given task in a listing sorted by the given
+// field into an opaque cursor.
+func EncodeCursor(task Task, sort string, descending bool) string {
+	keys := sortKeys(sort, descending)
+
+	encoded := encodedCursor{Sort: sort, Descending: descending}
+	for _, key := range keys {
+		value, _ := json.Marshal(key.Value(task))
+		encoded.Values = append(encoded.Values, value)
+	}
+
+	str, _ := json.Marshal(encoded)
+	return base64.RawURLEncoding.EncodeToString(str)
+}
+
+// Decodes a cursor into the position of the last task of the previous page.
+// An empty cursor decodes to nil, which points to the first page.
+func DecodeCursor(cursor string) (*Cursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	invalid := errors.NewExternalError("Invalid cursor.")
+
+	str, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalid
+	}
+
+	var encoded encodedCursor
+	if err := json.Unmarshal(str, &encoded); err != nil || !IsSortField(encoded.Sort) {
+		return nil, invalid
+	}
+
+	keys := sortKeys(encoded.Sort, encoded.Descending)
+	if len(encoded.Values) != len(keys) {
+		return nil, invalid
+	}
+
+	decoded := &Cursor{Sort: encoded.Sort, Descending: encoded.Descending}
+	for i, key := range keys {
+		value := reflect.New(reflect.TypeOf(key.Value(Task{})))
+		if err := json.Unmarshal(encoded.Values[i], value.Interface()); err != nil {
+			return nil, invalid
+		}
+
+		decoded.Values = append(decoded.Values, value.Elem().Interface())
+	}
+
+	if ValidateID(decoded.ID()) != nil {
+		return nil, invalid
+	}
+
+	return decoded, nil
+}
+
+func (p *PageRequest) Validate() error {
+	if p.Limit < 0 || p.Limit > MaxPageLimit {
+		return errors.NewExternalError("Invalid page limit.")
+	}
+
+	_, err := DecodeCursor(p.Cursor)
+	return err
+}
+
+// Returns the requested page size, or the default one if none was given.
+func (p *PageRequest) PageLimit() int {
+	if p.Limit == 0 {
+		return DefaultPageLimit
+	}
+
+	return p.Limit
+}
+
+// Builds a page out of tasks matching the given query, where tasks holds up
+// to one more task than the page limit to tell whether there is a next page.
+func NewTaskPage(tasks []Task, query TaskQuery) TaskPage {
+	limit := query.Page.PageLimit()
+
+	if len(tasks) <= limit {
+		return TaskPage{Tasks: tasks}
+	}
+
+	tasks = tasks[:limit]
+	return TaskPage{
+		Tasks:      tasks,
+		NextCursor: EncodeCursor(tasks[limit-1], query.SortField(), query.Descending),
+	}
+}
@@ -0,0 +1,127 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucsky/cuid"
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	task := Task{
+		ID:        cuid.New(),
+		Name:      "Task 1",
+		Priority:  PriorityHigh,
+		CreatedAt: time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	tests := map[string]struct {
+		cursor   string
+		expected *Cursor
+		err      string
+	}{
+		"Empty cursor": {
+			cursor:   "",
+			expected: nil,
+		},
+		"Cursor sorted by ID": {
+			cursor:   EncodeCursor(task, "id", false),
+			expected: &Cursor{Sort: "id", Values: []interface{}{task.ID}},
+		},
+		"Cursor sorted by name descending": {
+			cursor:   EncodeCursor(task, "name", true),
+			expected: &Cursor{Sort: "name", Descending: true, Values: []interface{}{task.Name, task.ID}},
+		},
+		"Cursor sorted by creation time": {
+			cursor:   EncodeCursor(task, "created_at", false),
+			expected: &Cursor{Sort: "created_at", Values: []interface{}{task.CreatedAt, task.ID}},
+		},
+		"Cursor sorted by priority": {
+			cursor:   EncodeCursor(task, "priority", false),
+			expected: &Cursor{Sort: "priority", Values: []interface{}{PriorityHigh, noDueDate, task.ID}},
+		},
+		"Malformed cursor": {
+			cursor: "not a cursor",
+			err:    "Invalid cursor.",
+		},
+		"Cursor with invalid ID": {
+			cursor: EncodeCursor(Task{ID: "1"}, "id", false),
+			err:    "Invalid cursor.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			cursor, err := DecodeCursor(test.cursor)
+			if test.err != "" {
+				assert.Equal(err.Error(), test.err)
+				assert.True(errors.IsExternal(err))
+			} else {
+				assert.NoError(err)
+				assert.Equal(test.expected, cursor)
+			}
+		})
+	}
+}
+
+func TestPageRequestValidate(t *testing.T) {
+	tests := map[string]struct {
+		page PageRequest
+		err  string
+	}{
+		"Default limit": {
+			page: PageRequest{},
+		},
+		"Valid limit": {
+			page: PageRequest{Limit: 10},
+		},
+		"Negative limit": {
+			page: PageRequest{Limit: -1},
+			err:  "Invalid page limit.",
+		},
+		"Limit too large": {
+			page: PageRequest{Limit: MaxPageLimit + 1},
+			err:  "Invalid page limit.",
+		},
+		"Invalid cursor": {
+			page: PageRequest{Cursor: "not a cursor"},
+			err:  "Invalid cursor.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := test.page.Validate()
+			if test.err != "" {
+				assert.Equal(err.Error(), test.err)
+				assert.True(errors.IsExternal(err))
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
+
+func TestNewTaskPage(t *testing.T) {
+	tasks := []Task{
+		{ID: "1", Name: "Task 1"},
+		{ID: "2", Name: "Task 2"},
+		{ID: "3", Name: "Task 3"},
+	}
+
+	assert := assert.New(t)
+
+	page := NewTaskPage(tasks, TaskQuery{Page: PageRequest{Limit: 3}})
+	assert.Equal(tasks, page.Tasks)
+	assert.Empty(page.NextCursor)
+
+	page = NewTaskPage(tasks, TaskQuery{Sort: "name", Page: PageRequest{Limit: 2}})
+	assert.Equal(tasks[:2], page.Tasks)
+	assert.Equal(EncodeCursor(tasks[1], "name", false), page.NextCursor)
+}
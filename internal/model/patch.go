@@ -0,0 +1,111 @@
+package model
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+)
+
+// Fields of a task that can be changed by a partial update, named as in JSON.
+// The project and position are changed by moving the task instead.
+var PatchableFields = []string{
+	"name", "description", "completed", "status", "priority", "parent_id",
+	"auto_complete", "recurrence", "due_at",
+}
+
+// A partial update of a task. Only the listed fields are taken from the task,
+// the rest keep their current values. A non-zero version must match the
+// current version of the task.
+type TaskPatch struct {
+	Fields []string
+	Task   Task
+}
+
+// Parses a JSON Merge Patch (RFC 7386) of a task. Fields set to null are reset
+// to their zero value.
+func ParseMergePatch(data []byte) (TaskPatch, error) {
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(data, &document); err != nil {
+		return TaskPatch{}, err
+	}
+
+	patch := TaskPatch{Fields: []string{}}
+	values := map[string]json.RawMessage{}
+
+	for field, value := range document {
+		patch.Fields = append(patch.Fields, field)
+
+		if string(value) != "null" {
+			values[field] = value
+		}
+	}
+
+	sort.Strings(patch.Fields)
+
+	if err := patch.Validate(); err != nil {
+		return TaskPatch{}, err
+	}
+
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return TaskPatch{}, err
+	}
+
+	if err := json.Unmarshal(encoded, &patch.Task); err != nil {
+		return TaskPatch{}, err
+	}
+
+	return patch, nil
+}
+
+// Checks that the patch only changes fields that can be patched.
+func (p TaskPatch) Validate() error {
+	for _, field := range p.Fields {
+		patchable := false
+		for _, f := range PatchableFields {
+			patchable = patchable || f == field
+		}
+
+		if !patchable {
+			return errors.NewExternalError("Invalid patch field: " + strconv.Quote(field) + ".")
+		}
+	}
+
+	return nil
+}
+
+// Returns the given task with the patched fields changed.
+func (p TaskPatch) Apply(current Task) Task {
+	task := current
+
+	for _, field := range p.Fields {
+		switch field {
+		case "name":
+			task.Name = p.Task.Name
+		case "description":
+			task.Description = p.Task.Description
+		case "completed":
+			task.Completed = p.Task.Completed
+		case "status":
+			task.Status = p.Task.Status
+		case "priority":
+			task.Priority = p.Task.Priority
+		case "parent_id":
+			task.ParentID = p.Task.ParentID
+		case "auto_complete":
+			task.AutoComplete = p.Task.AutoComplete
+		case "recurrence":
+			task.Recurrence = p.Task.Recurrence
+		case "due_at":
+			task.DueAt = p.Task.DueAt
+		}
+	}
+
+	if p.Task.Version != 0 {
+		task.Version = p.Task.Version
+	}
+
+	return task
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMergePatch(t *testing.T) {
+	dueAt := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		body     string
+		expected TaskPatch
+		err      string
+	}{
+		"Rename a task": {
+			body:     `{"name":"Task 1 Updated"}`,
+			expected: TaskPatch{Fields: []string{"name"}, Task: Task{Name: "Task 1 Updated"}},
+		},
+		"Change several fields": {
+			body: `{"priority":"high","due_at":"2022-03-01T12:00:00Z","completed":true}`,
+			expected: TaskPatch{
+				Fields: []string{"completed", "due_at", "priority"},
+				Task:   Task{Completed: true, Priority: PriorityHigh, DueAt: &dueAt},
+			},
+		},
+		"Reset fields with null": {
+			body:     `{"priority":null,"parent_id":null}`,
+			expected: TaskPatch{Fields: []string{"parent_id", "priority"}},
+		},
+		"Empty patch": {
+			body:     `{}`,
+			expected: TaskPatch{Fields: []string{}},
+		},
+		"Field that cannot be patched": {
+			body: `{"project_id":"cl09rb83d000009l13y5n5ur8"}`,
+			err:  "Invalid patch field: \"project_id\".",
+		},
+		"Invalid priority": {
+			body: `{"priority":"highest"}`,
+			err:  "Invalid task priority.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			patch, err := ParseMergePatch([]byte(test.body))
+			if test.err != "" {
+				assert.EqualError(err, test.err)
+				assert.True(errors.IsExternal(err))
+			} else {
+				assert.NoError(err)
+				assert.Equal(test.expected, patch)
+			}
+		})
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	assert := assert.New(t)
+	parentID := "cl09rb83d000009l13y5n5ur8"
+
+	current := Task{
+		ID:        "cl09rb83d000009l13y5n5ur9",
+		Name:      "Task 1",
+		Completed: true,
+		Status:    StatusDone,
+		Priority:  PriorityHigh,
+		ParentID:  &parentID,
+		Version:   2,
+	}
+
+	patched := TaskPatch{
+		Fields: []string{"name", "parent_id"},
+		Task:   Task{Name: "Task 1 Updated", Priority: PriorityLow},
+	}.Apply(current)
+
+	expected := current
+	expected.Name = "Task 1 Updated"
+	expected.ParentID = nil
+	assert.Equal(expected, patched)
+
+	patched = TaskPatch{Task: Task{Version: 1}}.Apply(current)
+	assert.Equal(int64(1), patched.Version)
+}
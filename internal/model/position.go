@@ -0,0 +1,148 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+)
+
+// Digits of task positions, in ascending byte order. Positions are compared
+// byte by byte, so there is always room for a new one between any two, and
+// moving a task never requires rewriting its neighbors.
+const positionDigits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Validates the neighbors a task is moved between: at least one of them must
+// be given, and neither can be the task itself.
+func ValidateNeighbors(id string, afterID *string, beforeID *string) error {
+	if afterID == nil && beforeID == nil {
+		return errors.NewExternalError("A neighbor task is required.")
+	}
+
+	for _, neighborID := range []*string{afterID, beforeID} {
+		if neighborID == nil {
+			continue
+		}
+
+		if err := ValidateID(*neighborID); err != nil {
+			return errors.NewExternalError("Invalid neighbor task ID.")
+		}
+
+		if *neighborID == id {
+			return errors.NewExternalError("A task cannot be moved next to itself.")
+		}
+	}
+
+	return nil
+}
+
+// Returns a position sorted after the lower one and before the upper one. An
+// empty lower position stands for the start of the list and an empty upper
+// position for its end.
+func PositionBetween(lower string, upper string) (string, error) {
+	if upper != "" && lower >= upper {
+		return "", errors.NewExternalError("Neighbor tasks are not in order.")
+	}
+
+	if upper == "" && lower != "" {
+		return positionAfter(lower), nil
+	}
+
+	return positionMidpoint(lower, upper), nil
+}
+
+// Returns a position after the given one, stepping by a fixed amount so that
+// appending tasks one after another keeps positions short. Positions are read
+// as counters made of a number of leading "z" digits followed by one more
+// digit than that, so that longer counters sort after shorter ones.
+func positionAfter(lower string) string {
+	zs := 0
+	for zs < len(lower) && lower[zs] == 'z' {
+		zs++
+	}
+
+	length := 2*zs + 1
+	counter := []byte(lower)
+	if len(counter) > length {
+		counter = counter[:length]
+	}
+
+	for len(counter) < length {
+		counter = append(counter, positionDigits[0])
+	}
+
+	// The digit after the leading "z" digits is never a "z", so the carry never
+	// goes past it.
+	for i := length - 1; i >= zs; i-- {
+		digit := strings.IndexByte(positionDigits, counter[i])
+		if digit < len(positionDigits)-1 {
+			counter[i] = positionDigits[digit+1]
+			break
+		}
+
+		counter[i] = positionDigits[0]
+	}
+
+	if counter[zs] == 'z' {
+		return strings.Repeat("z", zs+1) + strings.Repeat(positionDigits[:1], zs+1) + positionDigits[1:2]
+	}
+
+	// Positions never end with the lowest digit, so that there is always room
+	// before them.
+	if counter[length-1] == positionDigits[0] {
+		counter[length-1] = positionDigits[1]
+	}
+
+	return string(counter)
+}
+
+func positionMidpoint(lower string, upper string) string {
+	if upper != "" {
+		n := 0
+		for n < len(upper) && positionDigit(lower, n) == upper[n] {
+			n++
+		}
+
+		if n > 0 {
+			rest := ""
+			if n < len(lower) {
+				rest = lower[n:]
+			}
+
+			return upper[:n] + positionMidpoint(rest, upper[n:])
+		}
+	}
+
+	digitLower := 0
+	if lower != "" {
+		digitLower = strings.IndexByte(positionDigits, lower[0])
+	}
+
+	digitUpper := len(positionDigits)
+	if upper != "" {
+		digitUpper = strings.IndexByte(positionDigits, upper[0])
+	}
+
+	if digitUpper-digitLower > 1 {
+		return string(positionDigits[(digitLower+digitUpper+1)/2])
+	}
+
+	if len(upper) > 1 {
+		return upper[:1]
+	}
+
+	rest := ""
+	if lower != "" {
+		rest = lower[1:]
+	}
+
+	return string(positionDigits[digitLower]) + positionMidpoint(rest, "")
+}
+
+// Returns the digit of a position at the given index, padding it with zeros.
+func positionDigit(position string, i int) byte {
+	if i < len(position) {
+		return position[i]
+	}
+
+	return positionDigits[0]
+}
@@ -0,0 +1,177 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucsky/cuid"
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPositionBetween(t *testing.T) {
+	tests := map[string]struct {
+		lower    string
+		upper    string
+		expected string
+		err      string
+	}{
+		"Empty list": {
+			expected: "V",
+		},
+		"End of the list": {
+			lower:    "V",
+			expected: "W",
+		},
+		"End of the list after a midpoint": {
+			lower:    "FV",
+			expected: "G",
+		},
+		"End of the list after the last single digit": {
+			lower:    "y",
+			expected: "z01",
+		},
+		"End of the list after a longer position": {
+			lower:    "zyz",
+			expected: "zz001",
+		},
+		"End of the list after a carry": {
+			lower:    "z0z",
+			expected: "z11",
+		},
+		"Start of the list": {
+			upper:    "V",
+			expected: "G",
+		},
+		"Between two positions": {
+			lower:    "F",
+			upper:    "V",
+			expected: "N",
+		},
+		"Between adjacent positions": {
+			lower:    "F",
+			upper:    "G",
+			expected: "FV",
+		},
+		"Between positions sharing a prefix": {
+			lower:    "FV",
+			upper:    "FW",
+			expected: "FVV",
+		},
+		"Positions out of order": {
+			lower: "V",
+			upper: "F",
+			err:   "Neighbor tasks are not in order.",
+		},
+		"Same positions": {
+			lower: "V",
+			upper: "V",
+			err:   "Neighbor tasks are not in order.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			position, err := PositionBetween(test.lower, test.upper)
+			if test.err != "" {
+				assert.Equal(err.Error(), test.err)
+				assert.True(errors.IsExternal(err))
+			} else {
+				assert.NoError(err)
+				assert.Equal(test.expected, position)
+			}
+		})
+	}
+}
+
+func TestPositionBetweenRepeatedly(t *testing.T) {
+	assert := assert.New(t)
+
+	// Keeps inserting at the start, at the end and right after the first
+	// position, which is where positions grow the fastest.
+	positions := []string{"V"}
+	for i := 0; i < 200; i++ {
+		first, err := PositionBetween("", positions[0])
+		assert.NoError(err)
+
+		last, err := PositionBetween(positions[len(positions)-1], "")
+		assert.NoError(err)
+
+		second, err := PositionBetween(first, positions[0])
+		assert.NoError(err)
+
+		positions = append([]string{first, second}, append(positions, last)...)
+	}
+
+	for i, position := range positions {
+		assert.False(strings.HasSuffix(position, "0"), position)
+
+		if i > 0 {
+			assert.Less(positions[i-1], position)
+		}
+	}
+}
+
+func TestPositionBetweenAppending(t *testing.T) {
+	assert := assert.New(t)
+
+	// Appending grows positions by a fixed step, so they stay short however
+	// many tasks are created.
+	position := ""
+	for i := 0; i < 10000; i++ {
+		next, err := PositionBetween(position, "")
+		assert.NoError(err)
+		assert.Less(position, next)
+		assert.False(strings.HasSuffix(next, "0"), next)
+
+		position = next
+	}
+
+	assert.LessOrEqual(len(position), 5)
+}
+
+func TestValidateNeighbors(t *testing.T) {
+	id := cuid.New()
+	neighborID := cuid.New()
+	invalidID := "1"
+
+	tests := map[string]struct {
+		afterID  *string
+		beforeID *string
+		err      string
+	}{
+		"After a task": {
+			afterID: &neighborID,
+		},
+		"Before a task": {
+			beforeID: &neighborID,
+		},
+		"No neighbors": {
+			err: "A neighbor task is required.",
+		},
+		"Invalid neighbor ID": {
+			beforeID: &invalidID,
+			err:      "Invalid neighbor task ID.",
+		},
+		"Next to itself": {
+			afterID: &id,
+			err:     "A task cannot be moved next to itself.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := ValidateNeighbors(id, test.afterID, test.beforeID)
+			if test.err != "" {
+				assert.Equal(err.Error(), test.err)
+				assert.True(errors.IsExternal(err))
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
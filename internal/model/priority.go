@@ -0,0 +1,64 @@
+package model
+
+import (
+	"encoding/json"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+)
+
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+var priorityNames = []string{"none", "low", "medium", "high", "urgent"}
+
+func (p Priority) String() string {
+	if p < PriorityNone || p > PriorityUrgent {
+		return "invalid"
+	}
+
+	return priorityNames[p]
+}
+
+func ParsePriority(name string) (Priority, error) {
+	for i, n := range priorityNames {
+		if n == name {
+			return Priority(i), nil
+		}
+	}
+
+	return PriorityNone, errors.NewExternalError("Invalid task priority.")
+}
+
+func ValidatePriority(priority Priority) error {
+	if priority < PriorityNone || priority > PriorityUrgent {
+		return errors.NewExternalError("Invalid task priority.")
+	}
+
+	return nil
+}
+
+func (p Priority) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+func (p *Priority) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+
+	priority, err := ParsePriority(name)
+	if err != nil {
+		return err
+	}
+
+	*p = priority
+	return nil
+}
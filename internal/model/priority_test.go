@@ -0,0 +1,56 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePriority(t *testing.T) {
+	tests := map[string]struct {
+		priority Priority
+		err      string
+	}{
+		"No priority": {
+			priority: PriorityNone,
+		},
+		"Urgent priority": {
+			priority: PriorityUrgent,
+		},
+		"Invalid priority": {
+			priority: Priority(5),
+			err:      "Invalid task priority.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := ValidatePriority(test.priority)
+			if test.err != "" {
+				assert.Equal(err.Error(), test.err)
+				assert.True(errors.IsExternal(err))
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
+
+func TestPriorityJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	str, err := json.Marshal(PriorityHigh)
+	assert.NoError(err)
+	assert.Equal(`"high"`, string(str))
+
+	var priority Priority
+	assert.NoError(json.Unmarshal([]byte(`"urgent"`), &priority))
+	assert.Equal(PriorityUrgent, priority)
+
+	err = json.Unmarshal([]byte(`"whenever"`), &priority)
+	assert.True(errors.IsExternal(err))
+}
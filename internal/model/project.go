@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lucsky/cuid"
+	"github.com/mtbuzato/go-challenge/internal/errors"
+)
+
+// A named list that groups tasks. Every task belongs to at most one project.
+type Project struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func ValidateProjectID(id string) error {
+	if cuid.IsCuid(id) != nil {
+		return errors.NewExternalError("Invalid project ID.")
+	}
+
+	return nil
+}
+
+func ValidateProjectName(name string) error {
+	if name == "" {
+		return errors.NewExternalError("Invalid project name.")
+	}
+
+	if len(name) > 128 {
+		return errors.NewExternalError("Project name is too long.")
+	}
+
+	return nil
+}
+
+// Prepares a project to be created, assigning it a new ID and setting its
+// timestamps to the current time.
+func (p *Project) Init() {
+	p.ID = cuid.New()
+	p.CreatedAt = Now()
+	p.UpdatedAt = p.CreatedAt
+}
+
+func (p *Project) Validate() error {
+	err := ValidateProjectID(p.ID)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateProjectName(p.Name)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/lucsky/cuid"
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectValidate(t *testing.T) {
+	tests := map[string]struct {
+		project Project
+		err     string
+	}{
+		"Valid project": {
+			project: Project{ID: cuid.New(), Name: "Home"},
+		},
+		"Invalid project ID": {
+			project: Project{ID: "1", Name: "Home"},
+			err:     "Invalid project ID.",
+		},
+		"Invalid project name": {
+			project: Project{ID: cuid.New(), Name: ""},
+			err:     "Invalid project name.",
+		},
+		"Project name too long": {
+			project: Project{ID: cuid.New(), Name: "The name of this project is far too long to be accepted by the validator of the Project model so it should generate an error that says so."},
+			err:     "Project name is too long.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := test.project.Validate()
+			if test.err != "" {
+				assert.Equal(err.Error(), test.err)
+				assert.True(errors.IsExternal(err))
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
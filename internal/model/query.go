@@ -0,0 +1,115 @@
+package model
+
+import (
+	"time"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+)
+
+const DefaultSortField = "id"
+
+// Filters, sorting and pagination for task listings. Tasks in the trash are
+// never matched.
+type TaskQuery struct {
+	// Matches tasks whose name contains this text.
+	Name          string
+	Completed     *bool
+	Status        Status
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+
+	// Matches tasks in the project with this ID.
+	ProjectID string
+	// Matches tasks that are blocked by open tasks, or that are not.
+	Blocked *bool
+
+	// Matches incomplete tasks whose due date has passed.
+	Overdue bool
+	// Matches tasks due between now and this many days from now.
+	DueWithinDays int
+
+	// Matches tasks with any of these tags, or all of them when MatchAllTags
+	// is set.
+	Tags         []string
+	MatchAllTags bool
+
+	// Field to sort by, defaults to the ID. Ties are broken by ID.
+	Sort       string
+	Descending bool
+
+	Page PageRequest
+}
+
+// Returns the field to sort by.
+func (q *TaskQuery) SortField() string {
+	if q.Sort == "" {
+		return DefaultSortField
+	}
+
+	return q.Sort
+}
+
+// Returns the pattern to be used with LIKE to match task names containing
+// the query's name.
+func (q *TaskQuery) NamePattern() string {
+	return LikePattern(q.Name)
+}
+
+// Returns the position after which the requested page starts, or nil for the
+// first page.
+func (q *TaskQuery) After() *Cursor {
+	cursor, _ := DecodeCursor(q.Page.Cursor)
+	return cursor
+}
+
+func (q *TaskQuery) Validate() error {
+	if !IsSortField(q.SortField()) {
+		return errors.NewExternalError("Invalid sort field.")
+	}
+
+	if q.CreatedAfter != nil && q.CreatedBefore != nil && q.CreatedAfter.After(*q.CreatedBefore) {
+		return errors.NewExternalError("Invalid creation time range.")
+	}
+
+	if q.UpdatedAfter != nil && q.UpdatedBefore != nil && q.UpdatedAfter.After(*q.UpdatedBefore) {
+		return errors.NewExternalError("Invalid update time range.")
+	}
+
+	if q.Status != "" {
+		if err := ValidateStatus(q.Status); err != nil {
+			return err
+		}
+	}
+
+	if q.DueWithinDays < 0 {
+		return errors.NewExternalError("Invalid due date range.")
+	}
+
+	if q.ProjectID != "" {
+		if err := ValidateProjectID(q.ProjectID); err != nil {
+			return err
+		}
+	}
+
+	if len(q.Tags) > MaxQueryTags {
+		return errors.NewExternalError("Too many tags.")
+	}
+
+	for _, name := range q.TagNames() {
+		if err := ValidateTagName(name); err != nil {
+			return err
+		}
+	}
+
+	if err := q.Page.Validate(); err != nil {
+		return err
+	}
+
+	if cursor := q.After(); cursor != nil && (cursor.Sort != q.SortField() || cursor.Descending != q.Descending) {
+		return errors.NewExternalError("Invalid cursor.")
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucsky/cuid"
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskQueryValidate(t *testing.T) {
+	earlier := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+	task := Task{ID: cuid.New(), Name: "Task 1"}
+
+	tests := map[string]struct {
+		query TaskQuery
+		err   string
+	}{
+		"Empty query": {
+			query: TaskQuery{},
+		},
+		"Full query": {
+			query: TaskQuery{
+				Name:          "Task",
+				CreatedAfter:  &earlier,
+				CreatedBefore: &later,
+				UpdatedAfter:  &earlier,
+				UpdatedBefore: &later,
+				Sort:          "name",
+				Descending:    true,
+				Page:          PageRequest{Limit: 10, Cursor: EncodeCursor(task, "name", true)},
+			},
+		},
+		"Invalid sort field": {
+			query: TaskQuery{Sort: "deleted_at"},
+			err:   "Invalid sort field.",
+		},
+		"Invalid creation time range": {
+			query: TaskQuery{CreatedAfter: &later, CreatedBefore: &earlier},
+			err:   "Invalid creation time range.",
+		},
+		"Invalid update time range": {
+			query: TaskQuery{UpdatedAfter: &later, UpdatedBefore: &earlier},
+			err:   "Invalid update time range.",
+		},
+		"Invalid status": {
+			query: TaskQuery{Status: "blocked"},
+			err:   "Invalid task status.",
+		},
+		"Invalid project ID": {
+			query: TaskQuery{ProjectID: "1"},
+			err:   "Invalid project ID.",
+		},
+		"Invalid tag name": {
+			query: TaskQuery{Tags: []string{"backend", "needs review"}},
+			err:   "Invalid tag name.",
+		},
+		"Too many tags": {
+			query: TaskQuery{Tags: make([]string, MaxQueryTags+1)},
+			err:   "Too many tags.",
+		},
+		"Cursor for another sort field": {
+			query: TaskQuery{Sort: "created_at", Page: PageRequest{Cursor: EncodeCursor(task, "name", false)}},
+			err:   "Invalid cursor.",
+		},
+		"Cursor for another sort order": {
+			query: TaskQuery{Sort: "name", Page: PageRequest{Cursor: EncodeCursor(task, "name", true)}},
+			err:   "Invalid cursor.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := test.query.Validate()
+			if test.err != "" {
+				assert.Equal(err.Error(), test.err)
+				assert.True(errors.IsExternal(err))
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
+
+func TestTaskQueryNamePattern(t *testing.T) {
+	assert := assert.New(t)
+
+	query := TaskQuery{Name: `50%_off\`}
+	assert.Equal(`%50\%\_off\\%`, query.NamePattern())
+}
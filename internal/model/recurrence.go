@@ -0,0 +1,91 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/teambition/rrule-go"
+)
+
+// Maximum size of a recurrence rule, matching its column.
+const MaxRecurrenceLength = 255
+
+// Parses an iCalendar RRULE, such as "FREQ=WEEKLY;BYDAY=MO", with its
+// occurrences starting at the given time.
+func parseRecurrence(recurrence string, start time.Time) (*rrule.RRule, error) {
+	if strings.ContainsAny(recurrence, "\r\n") {
+		return nil, errors.NewExternalError("Invalid task recurrence rule.")
+	}
+
+	option, err := rrule.StrToROption(recurrence)
+	if err != nil {
+		return nil, errors.NewExternalError("Invalid task recurrence rule.")
+	}
+
+	option.Dtstart = start
+
+	rule, err := rrule.NewRRule(*option)
+	if err != nil {
+		return nil, errors.NewExternalError("Invalid task recurrence rule.")
+	}
+
+	return rule, nil
+}
+
+func ValidateRecurrence(recurrence string) error {
+	if recurrence == "" {
+		return nil
+	}
+
+	if len(recurrence) > MaxRecurrenceLength {
+		return errors.NewExternalError("Task recurrence rule is too long.")
+	}
+
+	_, err := parseRecurrence(recurrence, Now())
+	return err
+}
+
+// Returns the next occurrence of a recurring task completed at the given
+// time, due at the first date of its rule after its own due date, or after
+// the completion time when it has none. The rule is evaluated from the start
+// of the series, which is the first of these times when the task has not
+// recurred yet, and the start is carried over to the occurrence. Nil is
+// returned when the task does not recur or its rule has no more dates.
+func (t *Task) NextOccurrence(completedAt time.Time) (*Task, error) {
+	if t.Recurrence == "" {
+		return nil, nil
+	}
+
+	after := completedAt.UTC()
+	if t.DueAt != nil {
+		after = t.DueAt.UTC()
+	}
+
+	start := after
+	if t.RecurrenceStart != nil {
+		start = t.RecurrenceStart.UTC()
+	}
+
+	rule, err := parseRecurrence(t.Recurrence, start)
+	if err != nil {
+		return nil, err
+	}
+
+	dueAt := rule.After(after, false)
+	if dueAt.IsZero() {
+		return nil, nil
+	}
+
+	return &Task{
+		Name:            t.Name,
+		Description:     t.Description,
+		Priority:        t.Priority,
+		ProjectID:       t.ProjectID,
+		ParentID:        t.ParentID,
+		AutoComplete:    t.AutoComplete,
+		Recurrence:      t.Recurrence,
+		RecurrenceStart: &start,
+		DueAt:           &dueAt,
+	}, nil
+}
@@ -0,0 +1,155 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRecurrence(t *testing.T) {
+	tests := map[string]struct {
+		recurrence string
+		err        string
+	}{
+		"No recurrence": {
+			recurrence: "",
+		},
+		"Weekly": {
+			recurrence: "FREQ=WEEKLY;BYDAY=MO,TH",
+		},
+		"Prefixed rule": {
+			recurrence: "RRULE:FREQ=DAILY;INTERVAL=2",
+		},
+		"Unknown frequency": {
+			recurrence: "FREQ=SOMETIMES",
+			err:        "Invalid task recurrence rule.",
+		},
+		"Missing frequency": {
+			recurrence: "BYDAY=MO",
+			err:        "Invalid task recurrence rule.",
+		},
+		"Rule with a start date": {
+			recurrence: "DTSTART:20220301T120000Z\nRRULE:FREQ=DAILY",
+			err:        "Invalid task recurrence rule.",
+		},
+		"Rule that is too long": {
+			recurrence: "FREQ=DAILY;BYHOUR=" + strings.Repeat("1,", 128) + "1",
+			err:        "Task recurrence rule is too long.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := ValidateRecurrence(test.recurrence)
+			if test.err != "" {
+				assert.Equal(err.Error(), test.err)
+				assert.True(errors.IsExternal(err))
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	completedAt := time.Date(2022, 3, 2, 9, 30, 0, 0, time.UTC)
+	dueAt := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	nextWeek := time.Date(2022, 3, 7, 12, 0, 0, 0, time.UTC)
+	nextDay := time.Date(2022, 3, 3, 9, 30, 0, 0, time.UTC)
+	projectID := "cl09rb83d000009l13y5n5pr1"
+
+	tests := map[string]struct {
+		task     Task
+		expected *Task
+	}{
+		"Task that does not recur": {
+			task: Task{Name: "Task 1", DueAt: &dueAt},
+		},
+		"Weekly task with a due date": {
+			task: Task{Name: "Task 1", Priority: PriorityHigh, ProjectID: &projectID, Recurrence: "FREQ=WEEKLY;BYDAY=MO", DueAt: &dueAt},
+			expected: &Task{
+				Name:            "Task 1",
+				Priority:        PriorityHigh,
+				ProjectID:       &projectID,
+				Recurrence:      "FREQ=WEEKLY;BYDAY=MO",
+				RecurrenceStart: &dueAt,
+				DueAt:           &nextWeek,
+			},
+		},
+		"Daily task without a due date": {
+			task: Task{Name: "Task 1", Recurrence: "FREQ=DAILY"},
+			expected: &Task{
+				Name:            "Task 1",
+				Recurrence:      "FREQ=DAILY",
+				RecurrenceStart: &completedAt,
+				DueAt:           &nextDay,
+			},
+		},
+		"Task whose rule has ended": {
+			task: Task{Name: "Task 1", Recurrence: "FREQ=DAILY;UNTIL=20220301T000000Z", DueAt: &dueAt},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			next, err := test.task.NextOccurrence(completedAt)
+			assert.NoError(err)
+			assert.Equal(test.expected, next)
+		})
+	}
+}
+
+func TestNextOccurrenceSeries(t *testing.T) {
+	dueAt := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		recurrence string
+		expected   []time.Time
+	}{
+		"Rule with a count": {
+			recurrence: "FREQ=DAILY;COUNT=3",
+			expected: []time.Time{
+				time.Date(2022, 3, 2, 12, 0, 0, 0, time.UTC),
+				time.Date(2022, 3, 3, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		"Rule with an end date": {
+			recurrence: "FREQ=WEEKLY;UNTIL=20220316T000000Z",
+			expected: []time.Time{
+				time.Date(2022, 3, 8, 12, 0, 0, 0, time.UTC),
+				time.Date(2022, 3, 15, 12, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			task := &Task{Name: "Task 1", Recurrence: test.recurrence, DueAt: &dueAt}
+			dates := []time.Time{}
+
+			for i := 0; i <= len(test.expected); i++ {
+				next, err := task.NextOccurrence(*task.DueAt)
+				assert.NoError(err)
+
+				if next == nil {
+					break
+				}
+
+				assert.Equal(&dueAt, next.RecurrenceStart)
+				dates = append(dates, *next.DueAt)
+				task = next
+			}
+
+			assert.Equal(test.expected, dates)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+)
+
+const (
+	DefaultSearchLimit   = 50
+	MaxSearchLimit       = 1000
+	MaxSearchQueryLength = 256
+)
+
+func ValidateSearch(query string, limit int) error {
+	if len(SearchTerms(query)) == 0 {
+		return errors.NewExternalError("Invalid search query.")
+	}
+
+	if len(query) > MaxSearchQueryLength {
+		return errors.NewExternalError("Search query is too long.")
+	}
+
+	if limit < 0 || limit > MaxSearchLimit {
+		return errors.NewExternalError("Invalid search limit.")
+	}
+
+	return nil
+}
+
+// Returns the requested amount of search results, or the default one if none
+// was given.
+func SearchLimit(limit int) int {
+	if limit == 0 {
+		return DefaultSearchLimit
+	}
+
+	return limit
+}
+
+// Splits a search query into its distinct lowercase words.
+func SearchTerms(query string) []string {
+	terms := []string{}
+	seen := map[string]bool{}
+
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+
+	return terms
+}
+
+// Returns the pattern to be used with LIKE to match text containing the given
+// text.
+func LikePattern(text string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text)
+	return "%" + escaped + "%"
+}
+
+// Sorts tasks by how many of the search terms their names contain, keeping the
+// original order between tasks with the same relevance.
+func RankByRelevance(tasks []Task, terms []string) {
+	relevance := make(map[string]int, len(tasks))
+	for _, task := range tasks {
+		name := strings.ToLower(task.Name)
+		for _, term := range terms {
+			if strings.Contains(name, term) {
+				relevance[task.ID]++
+			}
+		}
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return relevance[tasks[i].ID] > relevance[tasks[j].ID]
+	})
+}
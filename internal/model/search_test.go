@@ -0,0 +1,69 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSearch(t *testing.T) {
+	tests := map[string]struct {
+		query string
+		limit int
+		err   string
+	}{
+		"Valid search": {
+			query: "groceries",
+		},
+		"Empty query": {
+			query: "   ",
+			err:   "Invalid search query.",
+		},
+		"Query too long": {
+			query: strings.Repeat("a", MaxSearchQueryLength+1),
+			err:   "Search query is too long.",
+		},
+		"Invalid limit": {
+			query: "groceries",
+			limit: -1,
+			err:   "Invalid search limit.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := ValidateSearch(test.query, test.limit)
+			if test.err != "" {
+				assert.Equal(err.Error(), test.err)
+				assert.True(errors.IsExternal(err))
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
+
+func TestSearchTerms(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]string{"buy", "milk"}, SearchTerms("  Buy milk buy "))
+	assert.Empty(SearchTerms(""))
+}
+
+func TestRankByRelevance(t *testing.T) {
+	assert := assert.New(t)
+
+	tasks := []Task{
+		{ID: "1", Name: "Buy bread"},
+		{ID: "2", Name: "Buy milk"},
+		{ID: "3", Name: "Drink milk"},
+	}
+
+	RankByRelevance(tasks, []string{"buy", "milk"})
+
+	assert.Equal([]string{"2", "1", "3"}, []string{tasks[0].ID, tasks[1].ID, tasks[2].ID})
+}
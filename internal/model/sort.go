@@ -0,0 +1,145 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// A key task listings can be sorted by: an SQL expression and the value it
+// evaluates to for a given task.
+type SortKey struct {
+	Expr       string
+	Descending bool
+	Value      func(t Task) interface{}
+}
+
+// Stands in for the due date of tasks without one, so they are sorted after
+// all others.
+var noDueDate = time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+
+var idSortKey = SortKey{
+	Expr:  "id",
+	Value: func(t Task) interface{} { return t.ID },
+}
+
+var dueAtSortKey = SortKey{
+	Expr: "COALESCE(due_at, CAST('9999-12-31 23:59:59' AS DATETIME))",
+	Value: func(t Task) interface{} {
+		if t.DueAt == nil {
+			return noDueDate
+		}
+
+		return *t.DueAt
+	},
+}
+
+// Fields tasks can be sorted by, mapped to the keys they are sorted by. Ties
+// are always broken by ID.
+var sortFields = map[string][]SortKey{
+	"id": {},
+	"name": {{
+		Expr:  "name",
+		Value: func(t Task) interface{} { return t.Name },
+	}},
+	"completed": {{
+		Expr:  "completed",
+		Value: func(t Task) interface{} { return t.Completed },
+	}},
+	"created_at": {{
+		Expr:  "created_at",
+		Value: func(t Task) interface{} { return t.CreatedAt },
+	}},
+	"updated_at": {{
+		Expr:  "updated_at",
+		Value: func(t Task) interface{} { return t.UpdatedAt },
+	}},
+	"position": {{
+		Expr:  "position",
+		Value: func(t Task) interface{} { return t.Position },
+	}},
+	"due_at": {dueAtSortKey},
+	// Most urgent first, then the ones due soonest.
+	"priority": {{
+		Expr:       "priority",
+		Descending: true,
+		Value:      func(t Task) interface{} { return t.Priority },
+	}, dueAtSortKey},
+}
+
+func IsSortField(field string) bool {
+	_, ok := sortFields[field]
+	return ok
+}
+
+func sortKeys(sort string, descending bool) []SortKey {
+	keys := append([]SortKey{}, sortFields[sort]...)
+	keys = append(keys, idSortKey)
+
+	if descending {
+		for i := range keys {
+			keys[i].Descending = !keys[i].Descending
+		}
+	}
+
+	return keys
+}
+
+// Returns the keys the results of the query are sorted by, ending with the ID.
+func (q *TaskQuery) SortKeys() []SortKey {
+	return sortKeys(q.SortField(), q.Descending)
+}
+
+// Returns the expressions to order the results of the query by.
+func (q *TaskQuery) OrderBy() string {
+	keys := q.SortKeys()
+
+	order := make([]string, len(keys))
+	for i, key := range keys {
+		order[i] = key.Expr + " ASC"
+		if key.Descending {
+			order[i] = key.Expr + " DESC"
+		}
+	}
+
+	return strings.Join(order, ", ")
+}
+
+// Returns the condition matching the tasks sorted after the query's cursor
+// and its arguments, or an empty condition for the first page.
+func (q *TaskQuery) AfterCondition() (string, []interface{}) {
+	cursor := q.After()
+	if cursor == nil {
+		return "", nil
+	}
+
+	keys := q.SortKeys()
+	alternatives := make([]string, len(keys))
+	args := []interface{}{}
+
+	for i, key := range keys {
+		conditions := []string{}
+		for j := 0; j < i; j++ {
+			conditions = append(conditions, keys[j].Expr+" = ?")
+			args = append(args, cursor.Values[j])
+		}
+
+		op := " > ?"
+		if key.Descending {
+			op = " < ?"
+		}
+
+		conditions = append(conditions, key.Expr+op)
+		args = append(args, cursor.Values[i])
+
+		alternatives[i] = strings.Join(conditions, " AND ")
+		if len(conditions) > 1 {
+			alternatives[i] = "(" + alternatives[i] + ")"
+		}
+	}
+
+	if len(alternatives) == 1 {
+		return alternatives[0], args
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
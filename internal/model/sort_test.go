@@ -0,0 +1,80 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucsky/cuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskQueryOrderBy(t *testing.T) {
+	tests := map[string]struct {
+		query    TaskQuery
+		expected string
+	}{
+		"Default sort": {
+			query:    TaskQuery{},
+			expected: "id ASC",
+		},
+		"Sorted by name descending": {
+			query:    TaskQuery{Sort: "name", Descending: true},
+			expected: "name DESC, id DESC",
+		},
+		"Sorted by priority": {
+			query:    TaskQuery{Sort: "priority"},
+			expected: "priority DESC, " + dueAtSortKey.Expr + " ASC, id ASC",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			assert.Equal(test.expected, test.query.OrderBy())
+		})
+	}
+}
+
+func TestTaskQueryAfterCondition(t *testing.T) {
+	dueAt := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	task := Task{ID: cuid.New(), Name: "Task 1", Priority: PriorityHigh, DueAt: &dueAt}
+
+	tests := map[string]struct {
+		query     TaskQuery
+		condition string
+		args      []interface{}
+	}{
+		"First page": {
+			query:     TaskQuery{},
+			condition: "",
+			args:      nil,
+		},
+		"Sorted by ID": {
+			query:     TaskQuery{Page: PageRequest{Cursor: EncodeCursor(task, "id", false)}},
+			condition: "id > ?",
+			args:      []interface{}{task.ID},
+		},
+		"Sorted by name descending": {
+			query:     TaskQuery{Sort: "name", Descending: true, Page: PageRequest{Cursor: EncodeCursor(task, "name", true)}},
+			condition: "(name < ? OR (name = ? AND id < ?))",
+			args:      []interface{}{task.Name, task.Name, task.ID},
+		},
+		"Sorted by priority": {
+			query: TaskQuery{Sort: "priority", Page: PageRequest{Cursor: EncodeCursor(task, "priority", false)}},
+			condition: "(priority < ? OR (priority = ? AND " + dueAtSortKey.Expr + " > ?) OR (priority = ? AND " +
+				dueAtSortKey.Expr + " = ? AND id > ?))",
+			args: []interface{}{PriorityHigh, PriorityHigh, dueAt, PriorityHigh, dueAt, task.ID},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			condition, args := test.query.AfterCondition()
+			assert.Equal(test.condition, condition)
+			assert.Equal(test.args, args)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+)
+
+// The workflow state of a task. A task is completed when it is done.
+type Status string
+
+const (
+	StatusTodo       Status = "todo"
+	StatusInProgress Status = "in_progress"
+	StatusReview     Status = "review"
+	StatusDone       Status = "done"
+)
+
+// Table of the statuses a task can move to from each status. Every workflow
+// must include StatusTodo, where new tasks start, and StatusDone.
+type Workflow map[Status][]Status
+
+var DefaultWorkflow = Workflow{
+	StatusTodo:       {StatusInProgress, StatusDone},
+	StatusInProgress: {StatusTodo, StatusReview, StatusDone},
+	StatusReview:     {StatusInProgress, StatusDone},
+	StatusDone:       {StatusTodo},
+}
+
+// The workflow enforced when tasks change status, replaced through SetWorkflow
+// to customize the statuses tasks go through.
+var taskWorkflow = DefaultWorkflow.clone()
+
+// Replaces the workflow enforced when tasks change status. The workflow is
+// copied, so changing it afterwards has no effect.
+func SetWorkflow(workflow Workflow) error {
+	if err := workflow.Validate(); err != nil {
+		return err
+	}
+
+	taskWorkflow = workflow.clone()
+	return nil
+}
+
+// Parses a workflow from a JSON object mapping each status to the statuses it
+// can move to, such as {"todo": ["done"], "done": ["todo"]}.
+func ParseWorkflow(data string) (Workflow, error) {
+	workflow := Workflow{}
+	if err := json.Unmarshal([]byte(data), &workflow); err != nil {
+		return nil, fmt.Errorf("Invalid workflow: %w", err)
+	}
+
+	if err := workflow.Validate(); err != nil {
+		return nil, err
+	}
+
+	return workflow, nil
+}
+
+// Validates that the workflow includes StatusTodo and StatusDone, and that
+// every status it moves to is part of it.
+func (w Workflow) Validate() error {
+	for _, status := range []Status{StatusTodo, StatusDone} {
+		if _, ok := w[status]; !ok {
+			return fmt.Errorf("Invalid workflow: missing status %s.", status)
+		}
+	}
+
+	for from, targets := range w {
+		if from == "" || len(from) > 32 {
+			return fmt.Errorf("Invalid workflow: invalid status %q.", from)
+		}
+
+		for _, to := range targets {
+			if _, ok := w[to]; !ok {
+				return fmt.Errorf("Invalid workflow: %s moves to unknown status %q.", from, to)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (w Workflow) clone() Workflow {
+	clone := make(Workflow, len(w))
+	for status, targets := range w {
+		clone[status] = append([]Status{}, targets...)
+	}
+
+	return clone
+}
+
+func ValidateStatus(status Status) error {
+	if _, ok := taskWorkflow[status]; !ok {
+		return errors.NewExternalError("Invalid task status.")
+	}
+
+	return nil
+}
+
+// Validates that a task can move from one status to another. Keeping the same
+// status is always allowed.
+func ValidateTransition(from Status, to Status) error {
+	if err := ValidateStatus(to); err != nil {
+		return err
+	}
+
+	if from == to {
+		return nil
+	}
+
+	for _, allowed := range taskWorkflow[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+
+	return errors.NewExternalError("Cannot move a task from " + string(from) + " to " + string(to) + ".")
+}
+
+// Returns the status matching a completion flag, used for clients that only
+// set whether a task is completed.
+func StatusOf(completed bool) Status {
+	if completed {
+		return StatusDone
+	}
+
+	return StatusTodo
+}
+
+// Fills in the status of a task from its completion flag when it has none,
+// then derives the completion flag from the status.
+func (t *Task) SyncStatus() {
+	if t.Status == "" {
+		t.Status = StatusOf(t.Completed)
+	}
+
+	t.Completed = t.Status == StatusDone
+}
+
+// Works out the status of an updated task from its current state. A changed
+// status takes precedence, otherwise changing the completion flag completes or
+// reopens the task and leaving it alone keeps the current status.
+func (t *Task) ResolveStatus(current Task) {
+	if t.Status == "" || t.Status == current.Status {
+		t.Status = current.Status
+		if t.Completed != current.Completed {
+			t.Status = StatusOf(t.Completed)
+		}
+	}
+
+	t.SyncStatus()
+}
@@ -0,0 +1,209 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTransition(t *testing.T) {
+	tests := map[string]struct {
+		from Status
+		to   Status
+		err  string
+	}{
+		"Same status": {
+			from: StatusReview,
+			to:   StatusReview,
+		},
+		"Start a task": {
+			from: StatusTodo,
+			to:   StatusInProgress,
+		},
+		"Complete a task under review": {
+			from: StatusReview,
+			to:   StatusDone,
+		},
+		"Reopen a task": {
+			from: StatusDone,
+			to:   StatusTodo,
+		},
+		"Review a task that was not started": {
+			from: StatusTodo,
+			to:   StatusReview,
+			err:  "Cannot move a task from todo to review.",
+		},
+		"Unknown status": {
+			from: StatusTodo,
+			to:   "blocked",
+			err:  "Invalid task status.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := ValidateTransition(test.from, test.to)
+			if test.err != "" {
+				assert.Equal(err.Error(), test.err)
+				assert.True(errors.IsExternal(err))
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
+
+func TestCustomWorkflow(t *testing.T) {
+	assert := assert.New(t)
+
+	defer SetWorkflow(DefaultWorkflow)
+
+	workflow := Workflow{
+		StatusTodo: {"approved"},
+		"approved": {StatusDone},
+		StatusDone: {},
+	}
+	assert.NoError(SetWorkflow(workflow))
+
+	// The workflow is copied, so changing it afterwards has no effect.
+	workflow[StatusTodo] = append(workflow[StatusTodo], StatusDone)
+
+	assert.NoError(ValidateTransition(StatusTodo, "approved"))
+	assert.Error(ValidateTransition(StatusTodo, StatusDone))
+	assert.Error(ValidateStatus(StatusInProgress))
+	assert.Equal([]Status{StatusInProgress, StatusDone}, DefaultWorkflow[StatusTodo])
+
+	// An invalid workflow is rejected and keeps the current one.
+	assert.Error(SetWorkflow(Workflow{StatusTodo: {StatusReview}, StatusDone: {}}))
+	assert.NoError(ValidateTransition(StatusTodo, "approved"))
+}
+
+func TestParseWorkflow(t *testing.T) {
+	tests := map[string]struct {
+		data        string
+		expected    Workflow
+		shouldError bool
+	}{
+		"valid": {
+			data:     `{"todo": ["approved"], "approved": ["done"], "done": []}`,
+			expected: Workflow{StatusTodo: {"approved"}, "approved": {StatusDone}, StatusDone: {}},
+		},
+		"invalid_json": {
+			data:        `{"todo": "done"}`,
+			shouldError: true,
+		},
+		"missing_todo": {
+			data:        `{"approved": ["done"], "done": []}`,
+			shouldError: true,
+		},
+		"missing_done": {
+			data:        `{"todo": ["approved"], "approved": []}`,
+			shouldError: true,
+		},
+		"unknown_target": {
+			data:        `{"todo": ["approved"], "done": ["todo"]}`,
+			shouldError: true,
+		},
+		"empty_status": {
+			data:        `{"todo": ["done"], "done": [], "": ["todo"]}`,
+			shouldError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			workflow, err := ParseWorkflow(test.data)
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				assert.Equal(test.expected, workflow)
+			}
+		})
+	}
+}
+
+func TestSyncStatus(t *testing.T) {
+	tests := map[string]struct {
+		task              Task
+		expectedStatus    Status
+		expectedCompleted bool
+	}{
+		"Open task without a status": {
+			task:           Task{},
+			expectedStatus: StatusTodo,
+		},
+		"Completed task without a status": {
+			task:              Task{Completed: true},
+			expectedStatus:    StatusDone,
+			expectedCompleted: true,
+		},
+		"Task in progress": {
+			task:           Task{Completed: true, Status: StatusInProgress},
+			expectedStatus: StatusInProgress,
+		},
+		"Done task": {
+			task:              Task{Status: StatusDone},
+			expectedStatus:    StatusDone,
+			expectedCompleted: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			test.task.SyncStatus()
+			assert.Equal(test.expectedStatus, test.task.Status)
+			assert.Equal(test.expectedCompleted, test.task.Completed)
+		})
+	}
+}
+
+func TestResolveStatus(t *testing.T) {
+	current := Task{Status: StatusReview}
+
+	tests := map[string]struct {
+		task              Task
+		expectedStatus    Status
+		expectedCompleted bool
+	}{
+		"Status left alone": {
+			task:           Task{},
+			expectedStatus: StatusReview,
+		},
+		"Status kept as is": {
+			task:           Task{Status: StatusReview},
+			expectedStatus: StatusReview,
+		},
+		"Task completed through its flag": {
+			task:              Task{Status: StatusReview, Completed: true},
+			expectedStatus:    StatusDone,
+			expectedCompleted: true,
+		},
+		"Task moved to another status": {
+			task:           Task{Status: StatusInProgress},
+			expectedStatus: StatusInProgress,
+		},
+		"Task completed through its status": {
+			task:              Task{Status: StatusDone},
+			expectedStatus:    StatusDone,
+			expectedCompleted: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			test.task.ResolveStatus(current)
+			assert.Equal(test.expectedStatus, test.task.Status)
+			assert.Equal(test.expectedCompleted, test.task.Completed)
+		})
+	}
+}
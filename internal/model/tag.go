@@ -0,0 +1,100 @@
+package model
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/lucsky/cuid"
+	"github.com/mtbuzato/go-challenge/internal/errors"
+)
+
+const MaxTagLength = 32
+
+// Maximum number of tags a task listing can be filtered by.
+const MaxQueryTags = 20
+
+// A label that can be attached to any number of tasks. Tag names are unique
+// and case-insensitive.
+type Tag struct {
+	ID   string `json:"id" gorm:"primaryKey"`
+	Name string `json:"name"`
+}
+
+// Returns the form in which the given tag name is stored.
+func NormalizeTagName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Validates a normalized tag name. Tag names may only contain letters,
+// digits, dashes and underscores.
+func ValidateTagName(name string) error {
+	if name == "" {
+		return errors.NewExternalError("Invalid tag name.")
+	}
+
+	if len(name) > MaxTagLength {
+		return errors.NewExternalError("Tag name is too long.")
+	}
+
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return errors.NewExternalError("Invalid tag name.")
+		}
+	}
+
+	return nil
+}
+
+// Creates a new tag with the given name, which is normalized and validated.
+func NewTag(name string) (Tag, error) {
+	name = NormalizeTagName(name)
+
+	if err := ValidateTagName(name); err != nil {
+		return Tag{}, err
+	}
+
+	return Tag{ID: cuid.New(), Name: name}, nil
+}
+
+// Returns the normalized names of the tags the query filters by, without
+// duplicates.
+func (q *TaskQuery) TagNames() []string {
+	names := []string{}
+	seen := map[string]bool{}
+
+	for _, tag := range q.Tags {
+		name := NormalizeTagName(tag)
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// Returns the SQL condition matching tasks with the query's tags and its
+// arguments, or an empty condition when the query has no tags. Tasks must
+// have at least one of the tags, or all of them when MatchAllTags is set.
+func (q *TaskQuery) TagCondition() (string, []interface{}) {
+	names := q.TagNames()
+	if len(names) == 0 {
+		return "", nil
+	}
+
+	placeholders := make([]string, len(names))
+	args := make([]interface{}, len(names))
+	for i, name := range names {
+		placeholders[i] = "?"
+		args[i] = name
+	}
+
+	condition := "id IN (SELECT task_tags.task_id FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE tags.name IN (" + strings.Join(placeholders, ", ") + ")"
+
+	if q.MatchAllTags {
+		condition += " GROUP BY task_tags.task_id HAVING COUNT(*) = ?"
+		args = append(args, len(names))
+	}
+
+	return condition + ")", args
+}
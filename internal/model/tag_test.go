@@ -0,0 +1,87 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTag(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		expected string
+		err      string
+	}{
+		"Valid tag": {
+			name:     "backend",
+			expected: "backend",
+		},
+		"Tag name is normalized": {
+			name:     "  Waiting-On_Review ",
+			expected: "waiting-on_review",
+		},
+		"Empty tag name": {
+			name: "   ",
+			err:  "Invalid tag name.",
+		},
+		"Tag name with spaces": {
+			name: "needs review",
+			err:  "Invalid tag name.",
+		},
+		"Tag name too long": {
+			name: "this-tag-name-is-far-too-long-to-be-used",
+			err:  "Tag name is too long.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			tag, err := NewTag(test.name)
+			if test.err != "" {
+				assert.Equal(err.Error(), test.err)
+				assert.True(errors.IsExternal(err))
+			} else {
+				assert.NoError(err)
+				assert.NoError(ValidateID(tag.ID))
+				assert.Equal(test.expected, tag.Name)
+			}
+		})
+	}
+}
+
+func TestTaskQueryTagCondition(t *testing.T) {
+	tests := map[string]struct {
+		query     TaskQuery
+		condition string
+		args      []interface{}
+	}{
+		"No tags": {
+			query:     TaskQuery{},
+			condition: "",
+			args:      nil,
+		},
+		"Any of the tags": {
+			query:     TaskQuery{Tags: []string{"backend", "Urgent", "backend"}},
+			condition: "id IN (SELECT task_tags.task_id FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE tags.name IN (?, ?))",
+			args:      []interface{}{"backend", "urgent"},
+		},
+		"All of the tags": {
+			query:     TaskQuery{Tags: []string{"backend", "urgent"}, MatchAllTags: true},
+			condition: "id IN (SELECT task_tags.task_id FROM task_tags JOIN tags ON tags.id = task_tags.tag_id WHERE tags.name IN (?, ?) GROUP BY task_tags.task_id HAVING COUNT(*) = ?)",
+			args:      []interface{}{"backend", "urgent", 2},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			condition, args := test.query.TagCondition()
+			assert.Equal(test.condition, condition)
+			assert.Equal(test.args, args)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"github.com/lucsky/cuid"
+	"github.com/mtbuzato/go-challenge/internal/errors"
+)
+
+// A task along with its subtasks, which are nested the same way.
+type TaskTree struct {
+	Task
+	Subtasks []TaskTree `json:"subtasks"`
+}
+
+func ValidateParentID(id string) error {
+	if cuid.IsCuid(id) != nil {
+		return errors.NewExternalError("Invalid parent task ID.")
+	}
+
+	return nil
+}
+
+// Validates that the task with the given ID can be placed under a parent with
+// the given ancestors, starting from the parent itself. A task can never end
+// up as a subtask of itself.
+func ValidateHierarchy(id string, ancestorIDs []string) error {
+	for _, ancestorID := range ancestorIDs {
+		if ancestorID == id {
+			return errors.NewExternalError("A task cannot be a subtask of itself.")
+		}
+	}
+
+	return nil
+}
+
+// Builds the tree rooted at the given task out of its descendants. Tasks
+// whose parent is not part of the tree are left out.
+func NewTaskTree(root Task, descendants []Task) TaskTree {
+	children := map[string][]Task{}
+	for _, task := range descendants {
+		if task.ParentID != nil {
+			children[*task.ParentID] = append(children[*task.ParentID], task)
+		}
+	}
+
+	return newTaskTree(root, children, map[string]bool{})
+}
+
+func newTaskTree(task Task, children map[string][]Task, seen map[string]bool) TaskTree {
+	seen[task.ID] = true
+
+	tree := TaskTree{Task: task, Subtasks: []TaskTree{}}
+	for _, child := range children[task.ID] {
+		if !seen[child.ID] {
+			tree.Subtasks = append(tree.Subtasks, newTaskTree(child, children, seen))
+		}
+	}
+
+	return tree
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/lucsky/cuid"
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateHierarchy(t *testing.T) {
+	id := cuid.New()
+	parentID := cuid.New()
+
+	tests := map[string]struct {
+		task Task
+		err  string
+	}{
+		"Task without a parent": {
+			task: Task{ID: id, Name: "Task 1"},
+		},
+		"Subtask": {
+			task: Task{ID: id, Name: "Task 1", ParentID: &parentID},
+		},
+		"Invalid parent ID": {
+			task: Task{ID: id, Name: "Task 1", ParentID: new(string)},
+			err:  "Invalid parent task ID.",
+		},
+		"Task that is its own parent": {
+			task: Task{ID: id, Name: "Task 1", ParentID: &id},
+			err:  "A task cannot be a subtask of itself.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := test.task.Validate()
+			if test.err != "" {
+				assert.Equal(err.Error(), test.err)
+				assert.True(errors.IsExternal(err))
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+
+	t.Run("Task under one of its subtasks", func(t *testing.T) {
+		assert := assert.New(t)
+
+		err := ValidateHierarchy(id, []string{parentID, id})
+		assert.True(errors.IsExternal(err))
+	})
+}
+
+func TestNewTaskTree(t *testing.T) {
+	assert := assert.New(t)
+
+	rootID, childID, orphanParentID := "1", "2", "9"
+	root := Task{ID: rootID, Name: "Task 1"}
+	child := Task{ID: childID, Name: "Task 2", ParentID: &rootID}
+	grandchild := Task{ID: "3", Name: "Task 3", ParentID: &childID}
+	sibling := Task{ID: "4", Name: "Task 4", ParentID: &rootID}
+	orphan := Task{ID: "5", Name: "Task 5", ParentID: &orphanParentID}
+
+	tree := NewTaskTree(root, []Task{child, grandchild, sibling, orphan})
+
+	assert.Equal(TaskTree{
+		Task: root,
+		Subtasks: []TaskTree{
+			{
+				Task: child,
+				Subtasks: []TaskTree{
+					{Task: grandchild, Subtasks: []TaskTree{}},
+				},
+			},
+			{Task: sibling, Subtasks: []TaskTree{}},
+		},
+	}, tree)
+}
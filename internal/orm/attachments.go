@@ -0,0 +1,163 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+// Lists the files attached to the task with the given ID, oldest first.
+func (r *TaskRepository) ListAttachments(ctx context.Context, id string) ([]model.Attachment, error) {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	attachments := []model.Attachment{}
+	res := r.conn(ctx).Where("task_id = ?", id).Order("created_at, id").Find(&attachments)
+	if res.Error != nil {
+		return nil, fmt.Errorf("Failed to query attachments: %w", res.Error)
+	}
+
+	return attachments, nil
+}
+
+// Gets the attachment with the given ID on the task with the given ID.
+func (r *TaskRepository) GetAttachment(ctx context.Context, id string, attachmentID string) (model.Attachment, error) {
+	if err := model.ValidateAttachmentID(attachmentID); err != nil {
+		return model.Attachment{}, err
+	}
+
+	// Attachments of tasks in the trash are hidden along with them.
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return model.Attachment{}, err
+	}
+
+	var attachment model.Attachment
+	res := r.conn(ctx).Limit(1).Find(&attachment, "id = ? AND task_id = ?", attachmentID, id)
+	if res.Error != nil {
+		return model.Attachment{}, fmt.Errorf("Failed to get attachment by ID: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return model.Attachment{}, errors.NewExternalError("Attachment not found.")
+	}
+
+	return attachment, nil
+}
+
+// Gets the attachment with the given ID on the task with the given ID along
+// with its content, which the caller must close.
+func (r *TaskRepository) OpenAttachment(ctx context.Context, id string, attachmentID string) (model.Attachment, io.ReadCloser, error) {
+	attachment, err := r.GetAttachment(ctx, id, attachmentID)
+	if err != nil {
+		return model.Attachment{}, nil, err
+	}
+
+	content, err := r.blobs.Open(attachment.ID)
+	if err != nil {
+		return model.Attachment{}, nil, fmt.Errorf("Failed to open attachment: %w", err)
+	}
+
+	return attachment, content, nil
+}
+
+// Attaches a file with the given content to the task the attachment
+// references and returns it. The ID, size and creation time are assigned by
+// the repository, and content over the size limit is rejected. The content is
+// removed again when the transaction the attachment is created in is rolled
+// back.
+func (r *TaskRepository) CreateAttachment(ctx context.Context, attachment model.Attachment, content io.Reader) (model.Attachment, error) {
+	attachment.Init()
+	attachment.Size = 0
+
+	if err := attachment.Validate(); err != nil {
+		return model.Attachment{}, err
+	}
+
+	if _, err := r.GetByID(ctx, attachment.TaskID); err != nil {
+		return model.Attachment{}, err
+	}
+
+	size, err := r.blobs.Put(attachment.ID, io.LimitReader(content, model.MaxAttachmentSize+1))
+	if err != nil {
+		return model.Attachment{}, fmt.Errorf("Failed to store attachment: %w", err)
+	}
+
+	attachment.Size = size
+
+	if err := model.ValidateAttachmentSize(attachment.Size); err != nil {
+		r.blobs.Delete(attachment.ID)
+		return model.Attachment{}, err
+	}
+
+	res := r.conn(ctx).Create(&attachment)
+	if res.Error != nil {
+		r.blobs.Delete(attachment.ID)
+		return model.Attachment{}, fmt.Errorf("Failed to create attachment: %w", res.Error)
+	}
+
+	r.afterRollback(ctx, func() {
+		r.blobs.Delete(attachment.ID)
+	})
+
+	return attachment, nil
+}
+
+// Deletes the attachment with the given ID from the task with the given ID,
+// along with its content once the deletion is committed.
+func (r *TaskRepository) DeleteAttachment(ctx context.Context, id string, attachmentID string) error {
+	if err := model.ValidateAttachmentID(attachmentID); err != nil {
+		return err
+	}
+
+	// Attachments of tasks in the trash are hidden along with them.
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	res := r.conn(ctx).Delete(&model.Attachment{}, "id = ? AND task_id = ?", attachmentID, id)
+	if res.Error != nil {
+		return fmt.Errorf("Failed to delete attachment: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return errors.NewExternalError("Attachment not found.")
+	}
+
+	// The content is kept until the deletion is committed, as it cannot be
+	// brought back when the transaction is rolled back.
+	return r.afterCommit(ctx, func() error {
+		if err := r.blobs.Delete(attachmentID); err != nil {
+			return fmt.Errorf("Failed to delete attachment content: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// Deletes the attachments of the tasks purged before the given time and
+// returns their IDs, leaving their content to be deleted by the caller.
+func (r *TaskRepository) purgeAttachments(ctx context.Context, before time.Time) ([]string, error) {
+	purged := r.conn(ctx).Model(&model.Task{}).Select("id").Where("deleted_at IS NOT NULL AND deleted_at < ?", before.UTC())
+
+	var ids []string
+	res := r.conn(ctx).Model(&model.Attachment{}).Where("task_id IN (?)", purged).Pluck("id", &ids)
+	if res.Error != nil {
+		return nil, fmt.Errorf("Failed to query purged attachments: %w", res.Error)
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	res = r.conn(ctx).Where("id IN ?", ids).Delete(&model.Attachment{})
+	if res.Error != nil {
+		return nil, fmt.Errorf("Failed to purge attachments: %w", res.Error)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,138 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+// Applies a batch of operations on behalf of the given actor. Updates are
+// applied one by one in order, then all creations and all deletions are each
+// written with a single statement. Operations that fail are reported in their
+// results, and when the batch is atomic a single failure leaves every task
+// untouched and returns a *model.BatchNotAppliedError with the results.
+func (r *TaskRepository) Batch(ctx context.Context, operations []model.BatchOperation, atomic bool, actor string) (model.BatchResponse, error) {
+	if err := model.ValidateBatch(operations); err != nil {
+		return model.BatchResponse{}, err
+	}
+
+	if err := model.ValidateActor(actor); err != nil {
+		return model.BatchResponse{}, err
+	}
+
+	results := make([]model.BatchResult, len(operations))
+	var response model.BatchResponse
+
+	// Failing when the batch is not applied rolls back the updates it made, even
+	// when it joined a transaction enclosing it.
+	err := r.Transaction(ctx, func(ctx context.Context) error {
+		created := []model.Task{}
+		deleted := []string{}
+
+		for i, operation := range operations {
+			var err error
+
+			switch operation.Action {
+			case model.BatchCreate:
+				var task model.Task
+				if task, err = r.prepareTask(ctx, operation.Task); err == nil {
+					created = append(created, task)
+					results[i].ID = task.ID
+				}
+			case model.BatchUpdate:
+				err = r.Patch(ctx, operation.ID, operation.Patch, actor)
+				results[i].ID = operation.ID
+			case model.BatchDelete:
+				if err = model.ValidateID(operation.ID); err == nil {
+					deleted = append(deleted, operation.ID)
+				}
+				results[i].ID = operation.ID
+			}
+
+			if err != nil {
+				if !errors.IsExternal(err) {
+					return err
+				}
+
+				results[i].Error = err.Error()
+			}
+		}
+
+		existing, err := r.existingTaskIDs(ctx, deleted)
+		if err != nil {
+			return err
+		}
+
+		deleted = deleted[:0]
+		for i, operation := range operations {
+			if operation.Action != model.BatchDelete || results[i].Error != "" {
+				continue
+			}
+
+			if existing[operation.ID] {
+				deleted = append(deleted, operation.ID)
+			} else {
+				results[i].Error = "Task not found."
+			}
+		}
+
+		response = model.NewBatchResponse(results, atomic)
+		if !response.Applied {
+			return &model.BatchNotAppliedError{Response: response}
+		}
+
+		if err := r.createTasks(ctx, created, actor); err != nil {
+			return err
+		}
+
+		return r.deleteTasks(ctx, deleted, actor)
+	})
+
+	if err != nil {
+		return model.BatchResponse{}, err
+	}
+
+	return response, nil
+}
+
+// Returns which of the given IDs belong to tasks that are not in the trash.
+func (r *TaskRepository) existingTaskIDs(ctx context.Context, ids []string) (map[string]bool, error) {
+	existing := map[string]bool{}
+	if len(ids) == 0 {
+		return existing, nil
+	}
+
+	found := []string{}
+	res := r.conn(ctx).Model(&model.Task{}).Where("id IN ? AND deleted_at IS NULL", ids).Pluck("id", &found)
+	if res.Error != nil {
+		return nil, fmt.Errorf("Failed to query tasks: %w", res.Error)
+	}
+
+	for _, id := range found {
+		existing[id] = true
+	}
+
+	return existing, nil
+}
+
+// Moves the tasks with the given IDs to the trash with a single statement and
+// records their deletion in their history.
+func (r *TaskRepository) deleteTasks(ctx context.Context, ids []string, actor string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	res := r.conn(ctx).Model(&model.Task{}).Where("id IN ? AND deleted_at IS NULL", ids).UpdateColumn("deleted_at", model.Now())
+	if res.Error != nil {
+		return fmt.Errorf("Failed to delete tasks: %w", res.Error)
+	}
+
+	entries := make([]model.HistoryEntry, len(ids))
+	for i, id := range ids {
+		entries[i] = model.NewHistoryEntry(id, model.HistoryDeleted, actor, nil)
+	}
+
+	return r.insertHistory(ctx, entries)
+}
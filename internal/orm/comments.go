@@ -0,0 +1,67 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+// Lists the comments on the task with the given ID, oldest first.
+func (r *TaskRepository) ListComments(ctx context.Context, id string) ([]model.Comment, error) {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	comments := []model.Comment{}
+	res := r.conn(ctx).Where("task_id = ?", id).Order("created_at, id").Find(&comments)
+	if res.Error != nil {
+		return nil, fmt.Errorf("Failed to query comments: %w", res.Error)
+	}
+
+	return comments, nil
+}
+
+// Creates a new comment on the task it references and returns it. The ID and
+// timestamps are assigned by the repository.
+func (r *TaskRepository) CreateComment(ctx context.Context, comment model.Comment) (model.Comment, error) {
+	comment.Init()
+
+	if err := comment.Validate(); err != nil {
+		return model.Comment{}, err
+	}
+
+	if _, err := r.GetByID(ctx, comment.TaskID); err != nil {
+		return model.Comment{}, err
+	}
+
+	res := r.conn(ctx).Create(&comment)
+	if res.Error != nil {
+		return model.Comment{}, fmt.Errorf("Failed to create comment: %w", res.Error)
+	}
+
+	return comment, nil
+}
+
+// Deletes the comment with the given ID from the task with the given ID.
+func (r *TaskRepository) DeleteComment(ctx context.Context, id string, commentID string) error {
+	if err := model.ValidateID(id); err != nil {
+		return err
+	}
+
+	if err := model.ValidateCommentID(commentID); err != nil {
+		return err
+	}
+
+	res := r.conn(ctx).Delete(&model.Comment{}, "id = ? AND task_id = ?", commentID, id)
+	if res.Error != nil {
+		return fmt.Errorf("Failed to delete comment: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return errors.NewExternalError("Comment not found.")
+	}
+
+	return nil
+}
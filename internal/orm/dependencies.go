@@ -0,0 +1,145 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+// Row of the table linking tasks to the tasks that block them.
+type taskDependency struct {
+	TaskID    string `gorm:"primaryKey"`
+	BlockerID string `gorm:"primaryKey"`
+}
+
+// Lists the tasks that block the task with the given ID and the tasks it
+// blocks. Tasks in the trash are left out.
+func (r *TaskRepository) ListDependencies(ctx context.Context, id string) (model.TaskDependencies, error) {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return model.TaskDependencies{}, err
+	}
+
+	blockedBy := []model.Task{}
+	res := r.conn(ctx).Where("id IN (?) AND deleted_at IS NULL", r.conn(ctx).Model(&taskDependency{}).Select("blocker_id").Where("task_id = ?", id)).Order("id").Find(&blockedBy)
+	if res.Error != nil {
+		return model.TaskDependencies{}, fmt.Errorf("Failed to query blockers: %w", res.Error)
+	}
+
+	blocks := []model.Task{}
+	res = r.conn(ctx).Where("id IN (?) AND deleted_at IS NULL", r.conn(ctx).Model(&taskDependency{}).Select("task_id").Where("blocker_id = ?", id)).Order("id").Find(&blocks)
+	if res.Error != nil {
+		return model.TaskDependencies{}, fmt.Errorf("Failed to query blocked tasks: %w", res.Error)
+	}
+
+	return model.TaskDependencies{BlockedBy: blockedBy, Blocks: blocks}, nil
+}
+
+// Marks the task with the given ID as blocked by another task. Dependencies
+// that would make a task block itself, directly or not, are rejected.
+func (r *TaskRepository) AddDependency(ctx context.Context, id string, blockerID string) error {
+	if err := model.ValidateDependency(id, blockerID, nil); err != nil {
+		return err
+	}
+
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	if _, err := r.GetByID(ctx, blockerID); err != nil {
+		if errors.IsExternal(err) {
+			return errors.NewExternalError("Blocker task not found.")
+		}
+
+		return err
+	}
+
+	blockerIDs, err := r.transitiveBlockerIDs(ctx, blockerID)
+	if err != nil {
+		return err
+	}
+
+	if err := model.ValidateDependency(id, blockerID, blockerIDs); err != nil {
+		return err
+	}
+
+	res := r.conn(ctx).Create(&taskDependency{TaskID: id, BlockerID: blockerID})
+	if res.Error != nil {
+		if mysqlErr, ok := res.Error.(*gomysql.MySQLError); !ok || mysqlErr.Number != errDuplicateEntry {
+			return fmt.Errorf("Failed to add dependency: %w", res.Error)
+		}
+	}
+
+	return nil
+}
+
+// Removes the dependency of the task with the given ID on another task.
+func (r *TaskRepository) RemoveDependency(ctx context.Context, id string, blockerID string) error {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	res := r.conn(ctx).Where("task_id = ? AND blocker_id = ?", id, blockerID).Delete(&taskDependency{})
+	if res.Error != nil {
+		return fmt.Errorf("Failed to remove dependency: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return errors.NewExternalError("Dependency not found.")
+	}
+
+	return nil
+}
+
+// Returns the IDs of every task that the task with the given ID is directly or
+// indirectly blocked by.
+func (r *TaskRepository) transitiveBlockerIDs(ctx context.Context, id string) ([]string, error) {
+	blockerIDs := []string{}
+	seen := map[string]bool{id: true}
+
+	for taskIDs := []string{id}; len(taskIDs) > 0; {
+		next := []string{}
+		res := r.conn(ctx).Model(&taskDependency{}).Where("task_id IN ?", taskIDs).Pluck("blocker_id", &next)
+		if res.Error != nil {
+			return nil, fmt.Errorf("Failed to query blockers: %w", res.Error)
+		}
+
+		taskIDs = nil
+		for _, blockerID := range next {
+			if !seen[blockerID] {
+				seen[blockerID] = true
+				blockerIDs = append(blockerIDs, blockerID)
+				taskIDs = append(taskIDs, blockerID)
+			}
+		}
+	}
+
+	return blockerIDs, nil
+}
+
+// Returns how many open tasks block the task with the given ID.
+func (r *TaskRepository) countOpenBlockers(ctx context.Context, id string) (int64, error) {
+	var count int64
+	res := r.conn(ctx).Model(&taskDependency{}).Joins("JOIN tasks ON tasks.id = task_dependencies.blocker_id").Where("task_dependencies.task_id = ? AND tasks.completed = ? AND tasks.deleted_at IS NULL", id, false).Count(&count)
+	if res.Error != nil {
+		return 0, fmt.Errorf("Failed to count blockers: %w", res.Error)
+	}
+
+	return count, nil
+}
+
+// Checks that the task with the given ID is not blocked by any open task.
+func (r *TaskRepository) validateUnblocked(ctx context.Context, id string) error {
+	count, err := r.countOpenBlockers(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return errors.NewExternalError("Task is blocked by open tasks.")
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package orm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+// Row of the task history table, with the field changes JSON encoded.
+type historyEntry struct {
+	ID        string `gorm:"primaryKey"`
+	TaskID    string
+	Action    model.HistoryAction
+	Actor     string
+	Changes   []byte
+	CreatedAt time.Time
+}
+
+func (historyEntry) TableName() string {
+	return "task_history"
+}
+
+// Lists the changes made to the task with the given ID, oldest first. The
+// history of tasks in the trash is kept until they are purged.
+func (r *TaskRepository) GetHistory(ctx context.Context, id string) ([]model.HistoryEntry, error) {
+	if err := model.ValidateID(id); err != nil {
+		return nil, err
+	}
+
+	var count int64
+	res := r.conn(ctx).Model(&model.Task{}).Where("id = ?", id).Count(&count)
+	if res.Error != nil {
+		return nil, fmt.Errorf("Failed to get task by ID: %w", res.Error)
+	}
+
+	if count == 0 {
+		return nil, errors.NewExternalError("Task not found.")
+	}
+
+	rows := []historyEntry{}
+	res = r.conn(ctx).Where("task_id = ?", id).Order("created_at, id").Find(&rows)
+	if res.Error != nil {
+		return nil, fmt.Errorf("Failed to query task history: %w", res.Error)
+	}
+
+	entries := make([]model.HistoryEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = model.HistoryEntry{
+			ID:        row.ID,
+			TaskID:    row.TaskID,
+			Action:    row.Action,
+			Actor:     row.Actor,
+			CreatedAt: row.CreatedAt,
+		}
+
+		if err := json.Unmarshal(row.Changes, &entries[i].Changes); err != nil {
+			return nil, fmt.Errorf("Failed to decode task history: %w", err)
+		}
+	}
+
+	return entries, nil
+}
+
+// Records a change made to a task by the given actor in its history.
+func (r *TaskRepository) recordHistory(ctx context.Context, taskID string, action model.HistoryAction, actor string, changes []model.FieldChange) error {
+	return r.insertHistory(ctx, []model.HistoryEntry{model.NewHistoryEntry(taskID, action, actor, changes)})
+}
+
+// Inserts the given history entries with a single statement.
+func (r *TaskRepository) insertHistory(ctx context.Context, entries []model.HistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	rows := make([]historyEntry, len(entries))
+	for i, entry := range entries {
+		encoded, err := json.Marshal(entry.Changes)
+		if err != nil {
+			return fmt.Errorf("Failed to encode task history: %w", err)
+		}
+
+		rows[i] = historyEntry{
+			ID:        entry.ID,
+			TaskID:    entry.TaskID,
+			Action:    entry.Action,
+			Actor:     entry.Actor,
+			Changes:   encoded,
+			CreatedAt: entry.CreatedAt,
+		}
+	}
+
+	res := r.conn(ctx).Create(&rows)
+	if res.Error != nil {
+		return fmt.Errorf("Failed to record task history: %w", res.Error)
+	}
+
+	return nil
+}
@@ -1,20 +1,31 @@
 package orm
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
-	"github.com/lucsky/cuid"
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/mtbuzato/go-challenge/internal/blob"
+	"github.com/mtbuzato/go-challenge/internal/errors"
 	"github.com/mtbuzato/go-challenge/internal/model"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// MySQL error returned when there is no FULLTEXT index for a MATCH query.
+const errNoFullTextIndex = 1191
+
 type TaskRepository struct {
 	gormDB *gorm.DB
+	blobs  blob.Store
 }
 
-func NewTaskRepository(db *sql.DB) (*TaskRepository, error) {
+// Creates a repository storing tasks in the given database and the contents
+// of their attachments in the given blob store.
+func NewTaskRepository(db *sql.DB, blobs blob.Store) (*TaskRepository, error) {
 	gormDB, err := gorm.Open(mysql.New(mysql.Config{
 		Conn: db,
 	}), &gorm.Config{})
@@ -22,13 +33,25 @@ func NewTaskRepository(db *sql.DB) (*TaskRepository, error) {
 		return nil, fmt.Errorf("Failed to open GORM: %w", err)
 	}
 
-	return &TaskRepository{gormDB}, nil
+	return &TaskRepository{gormDB, blobs}, nil
+}
+
+// Lists all tasks that are not in the trash in their manual order.
+func (r *TaskRepository) ListAll(ctx context.Context) ([]model.Task, error) {
+	tasks := []model.Task{}
+	res := r.conn(ctx).Where("deleted_at IS NULL").Order("position, id").Find(&tasks)
+	if res.Error != nil {
+		return nil, fmt.Errorf("Failed to query tasks: %w", res.Error)
+	}
+
+	return tasks, nil
 }
 
-// Lists all tasks.
-func (r *TaskRepository) ListAll() ([]model.Task, error) {
+// Lists all tasks that are not in the trash with the matching completion status
+// in their manual order.
+func (r *TaskRepository) ListByCompletion(ctx context.Context, completed bool) ([]model.Task, error) {
 	tasks := []model.Task{}
-	res := r.gormDB.Find(&tasks)
+	res := r.conn(ctx).Where("completed = ? AND deleted_at IS NULL", completed).Order("position, id").Find(&tasks)
 	if res.Error != nil {
 		return nil, fmt.Errorf("Failed to query tasks: %w", res.Error)
 	}
@@ -36,10 +59,128 @@ func (r *TaskRepository) ListAll() ([]model.Task, error) {
 	return tasks, nil
 }
 
-// Lists all tasks with the matching completion status.
-func (r *TaskRepository) ListByCompletion(completed bool) ([]model.Task, error) {
+// Lists a page of tasks matching the given query.
+func (r *TaskRepository) Query(ctx context.Context, query model.TaskQuery) (model.TaskPage, error) {
+	if err := query.Validate(); err != nil {
+		return model.TaskPage{}, err
+	}
+
+	db := r.conn(ctx).Where("deleted_at IS NULL")
+
+	if query.Name != "" {
+		db = db.Where("name LIKE ?", query.NamePattern())
+	}
+
+	if query.Completed != nil {
+		db = db.Where("completed = ?", *query.Completed)
+	}
+
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+
+	if query.ProjectID != "" {
+		db = db.Where("project_id = ?", query.ProjectID)
+	}
+
+	if query.CreatedAfter != nil {
+		db = db.Where("created_at >= ?", *query.CreatedAfter)
+	}
+
+	if query.CreatedBefore != nil {
+		db = db.Where("created_at < ?", *query.CreatedBefore)
+	}
+
+	if query.UpdatedAfter != nil {
+		db = db.Where("updated_at >= ?", *query.UpdatedAfter)
+	}
+
+	if query.UpdatedBefore != nil {
+		db = db.Where("updated_at < ?", *query.UpdatedBefore)
+	}
+
+	now := model.Now()
+
+	if query.Overdue {
+		db = db.Where("due_at < ? AND completed = ?", now, false)
+	}
+
+	if query.DueWithinDays > 0 {
+		db = db.Where("due_at >= ? AND due_at < ?", now, now.AddDate(0, 0, query.DueWithinDays))
+	}
+
+	if tags, tagArgs := query.TagCondition(); tags != "" {
+		db = db.Where(tags, tagArgs...)
+	}
+
+	if blocked, blockedArgs := query.BlockedCondition(); blocked != "" {
+		db = db.Where(blocked, blockedArgs...)
+	}
+
+	if after, afterArgs := query.AfterCondition(); after != "" {
+		db = db.Where(after, afterArgs...)
+	}
+
+	limit := query.Page.PageLimit()
+
+	tasks := []model.Task{}
+	res := db.Order(query.OrderBy()).Limit(limit + 1).Find(&tasks)
+	if res.Error != nil {
+		return model.TaskPage{}, fmt.Errorf("Failed to query tasks: %w", res.Error)
+	}
+
+	return model.NewTaskPage(tasks, query), nil
+}
+
+// Searches tasks that are not in the trash by the words in their names and
+// returns them ranked by relevance. On MySQL this requires a FULLTEXT index
+// on the task names, otherwise a slower pattern search is used.
+func (r *TaskRepository) Search(ctx context.Context, query string, limit int) ([]model.Task, error) {
+	if err := model.ValidateSearch(query, limit); err != nil {
+		return nil, err
+	}
+
+	limit = model.SearchLimit(limit)
+	tasks := []model.Task{}
+
+	if r.gormDB.Dialector.Name() == "mysql" {
+		res := r.conn(ctx).
+			Where("deleted_at IS NULL AND MATCH(name) AGAINST(? IN NATURAL LANGUAGE MODE)", query).
+			Clauses(clause.OrderBy{Expression: clause.Expr{SQL: "MATCH(name) AGAINST(? IN NATURAL LANGUAGE MODE) DESC, id", Vars: []interface{}{query}}}).
+			Limit(limit).
+			Find(&tasks)
+		if res.Error == nil {
+			return tasks, nil
+		}
+
+		if mysqlErr, ok := res.Error.(*gomysql.MySQLError); !ok || mysqlErr.Number != errNoFullTextIndex {
+			return nil, fmt.Errorf("Failed to search tasks: %w", res.Error)
+		}
+	}
+
+	terms := model.SearchTerms(query)
+	db := r.conn(ctx).Where("LOWER(name) LIKE ?", model.LikePattern(terms[0]))
+	for _, term := range terms[1:] {
+		db = db.Or("LOWER(name) LIKE ?", model.LikePattern(term))
+	}
+
+	res := r.conn(ctx).Where("deleted_at IS NULL").Where(db).Order("id").Find(&tasks)
+	if res.Error != nil {
+		return nil, fmt.Errorf("Failed to search tasks: %w", res.Error)
+	}
+
+	model.RankByRelevance(tasks, terms)
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+
+	return tasks, nil
+}
+
+// Lists all tasks in the trash.
+func (r *TaskRepository) ListTrash(ctx context.Context) ([]model.Task, error) {
 	tasks := []model.Task{}
-	res := r.gormDB.Where("completed = ?", completed).Find(&tasks)
+	res := r.conn(ctx).Where("deleted_at IS NOT NULL").Find(&tasks)
 	if res.Error != nil {
 		return nil, fmt.Errorf("Failed to query tasks: %w", res.Error)
 	}
@@ -47,46 +188,329 @@ func (r *TaskRepository) ListByCompletion(completed bool) ([]model.Task, error)
 	return tasks, nil
 }
 
-// Gets a task by ID and returns it.
-func (r *TaskRepository) GetByID(id string) (model.Task, error) {
+// Gets a task by ID and returns it. Tasks in the trash are not returned.
+func (r *TaskRepository) GetByID(ctx context.Context, id string) (model.Task, error) {
 	if err := model.ValidateID(id); err != nil {
 		return model.Task{}, err
 	}
 
 	var task model.Task
-	res := r.gormDB.First(&task, "id = ?", id)
+	res := r.conn(ctx).Limit(1).Find(&task, "id = ? AND deleted_at IS NULL", id)
 	if res.Error != nil {
 		return model.Task{}, fmt.Errorf("Failed to get task by ID: %w", res.Error)
 	}
 
+	if res.RowsAffected == 0 {
+		return model.Task{}, errors.NewExternalError("Task not found.")
+	}
+
 	return task, nil
 }
 
-// Creates a new task with the given name and returns it.
-func (r *TaskRepository) Create(name string) (model.Task, error) {
-	if err := model.ValidateName(name); err != nil {
+// Creates a new task from the given one on behalf of the given actor and
+// returns it. The ID and timestamps are assigned by the repository, and the
+// task is placed after all others.
+func (r *TaskRepository) Create(ctx context.Context, task model.Task, actor string) (model.Task, error) {
+	if err := model.ValidateActor(actor); err != nil {
 		return model.Task{}, err
 	}
 
-	task := model.Task{ID: cuid.New(), Name: name, Completed: false}
-	res := r.gormDB.Create(&task)
-	if res.Error != nil {
-		return model.Task{}, fmt.Errorf("Failed to create task: %w", res.Error)
+	task, err := r.prepareTask(ctx, task)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	tasks := []model.Task{task}
+	if err := r.createTasks(ctx, tasks, actor); err != nil {
+		return model.Task{}, err
+	}
+
+	return tasks[0], nil
+}
+
+// Prepares a task to be created, checking that it is valid and that its
+// project and parent exist.
+func (r *TaskRepository) prepareTask(ctx context.Context, task model.Task) (model.Task, error) {
+	task.Init()
+
+	if err := task.Validate(); err != nil {
+		return model.Task{}, err
+	}
+
+	if task.ProjectID != nil {
+		if _, err := r.GetProject(ctx, *task.ProjectID); err != nil {
+			return model.Task{}, err
+		}
+	}
+
+	if task.ParentID != nil {
+		if err := r.validateParent(ctx, task.ID, *task.ParentID); err != nil {
+			return model.Task{}, err
+		}
 	}
 
 	return task, nil
 }
 
-// Updates the given task.
-func (r *TaskRepository) Update(task model.Task) error {
+// Inserts the given prepared tasks with a single statement, placing them after
+// all others in order, and records their creation in their history.
+func (r *TaskRepository) createTasks(ctx context.Context, tasks []model.Task, actor string) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	position, err := r.nextPosition(ctx)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]model.HistoryEntry, len(tasks))
+
+	for i := range tasks {
+		if i > 0 {
+			if position, err = model.PositionBetween(position, ""); err != nil {
+				return err
+			}
+		}
+
+		tasks[i].Position = position
+		entries[i] = model.NewHistoryEntry(tasks[i].ID, model.HistoryCreated, actor, model.DiffTasks(model.Task{}, tasks[i]))
+	}
+
+	res := r.conn(ctx).Create(&tasks)
+	if res.Error != nil {
+		return fmt.Errorf("Failed to create task: %w", res.Error)
+	}
+
+	return r.insertHistory(ctx, entries)
+}
+
+// Updates the given task on behalf of the given actor, recording the changed
+// fields in its history. When the task has a version it must match the current
+// one, and either way the update fails with a conflict if the task is changed
+// by someone else in the meantime. The completion time is set when the task is
+// first completed and cleared when it is reopened. Status changes must be
+// allowed by the task workflow, and tasks blocked by open tasks cannot be
+// completed. Completing a recurring task creates its next occurrence, and
+// completing a task may complete its parents too. Tasks in the trash are left
+// untouched.
+func (r *TaskRepository) Update(ctx context.Context, task model.Task, actor string) error {
 	if err := task.Validate(); err != nil {
 		return err
 	}
 
-	res := r.gormDB.Save(&task)
+	if err := model.ValidateActor(actor); err != nil {
+		return err
+	}
+
+	current, err := r.GetByID(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+
+	if task.Version == 0 {
+		task.Version = current.Version
+	}
+
+	if task.Version != current.Version {
+		return errors.NewConflictError("Task has been changed since it was read.")
+	}
+
+	// The project and position are changed by moving the task instead.
+	task.ProjectID = current.ProjectID
+	task.Position = current.Position
+
+	// A recurring task keeps the start of its series until its rule changes.
+	task.RecurrenceStart = nil
+	if task.Recurrence == current.Recurrence {
+		task.RecurrenceStart = current.RecurrenceStart
+	}
+
+	task.ResolveStatus(current)
+
+	if err := model.ValidateTransition(current.Status, task.Status); err != nil {
+		return err
+	}
+
+	if task.ParentID != nil {
+		if err := r.validateParent(ctx, task.ID, *task.ParentID); err != nil {
+			return err
+		}
+	}
+
+	// Only completing a task requires its blockers to be done, so that a
+	// completed task can still be edited after one of them is reopened.
+	if task.Completed && !current.Completed {
+		if err := r.validateUnblocked(ctx, task.ID); err != nil {
+			return err
+		}
+	}
+
+	now := model.Now()
+
+	res := r.conn(ctx).Model(&model.Task{}).Where("id = ? AND version = ? AND deleted_at IS NULL", task.ID, task.Version).Updates(map[string]interface{}{
+		"name":             task.Name,
+		"description":      task.Description,
+		"completed":        task.Completed,
+		"status":           task.Status,
+		"completed_at":     gorm.Expr("CASE WHEN ? THEN COALESCE(completed_at, ?) ELSE NULL END", task.Completed, now),
+		"priority":         task.Priority,
+		"parent_id":        task.ParentID,
+		"auto_complete":    task.AutoComplete,
+		"recurrence":       task.Recurrence,
+		"due_at":           task.DueAt,
+		"recurrence_start": task.RecurrenceStart,
+		"updated_at":       now,
+		"version":          gorm.Expr("version + 1"),
+	})
 	if res.Error != nil {
 		return fmt.Errorf("Failed to update task: %w", res.Error)
 	}
 
+	if res.RowsAffected == 0 {
+		return errors.NewConflictError("Task has been changed since it was read.")
+	}
+
+	if changes := model.DiffTasks(current, task); len(changes) > 0 {
+		if err := r.recordHistory(ctx, task.ID, model.HistoryUpdated, actor, changes); err != nil {
+			return err
+		}
+	}
+
+	if task.Completed && !current.Completed && task.Recurrence != "" {
+		if err := r.createNextOccurrence(ctx, task, now, actor); err != nil {
+			return err
+		}
+	}
+
+	if task.Completed && task.ParentID != nil {
+		return r.completeParents(ctx, *task.ParentID, actor)
+	}
+
 	return nil
 }
+
+// Partially updates the task with the given ID on behalf of the given actor,
+// changing only the fields listed in the patch. The rest of the task is kept
+// and updated as a whole, so the same rules and version checks apply.
+func (r *TaskRepository) Patch(ctx context.Context, id string, patch model.TaskPatch, actor string) error {
+	if err := patch.Validate(); err != nil {
+		return err
+	}
+
+	current, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return r.Update(ctx, patch.Apply(current), actor)
+}
+
+// Moves the task with the given ID to the trash on behalf of the given actor.
+func (r *TaskRepository) Delete(ctx context.Context, id string, actor string) error {
+	if err := model.ValidateID(id); err != nil {
+		return err
+	}
+
+	if err := model.ValidateActor(actor); err != nil {
+		return err
+	}
+
+	res := r.conn(ctx).Model(&model.Task{}).Where("id = ? AND deleted_at IS NULL", id).UpdateColumn("deleted_at", model.Now())
+	if res.Error != nil {
+		return fmt.Errorf("Failed to delete task: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return errors.NewExternalError("Task not found.")
+	}
+
+	return r.recordHistory(ctx, id, model.HistoryDeleted, actor, nil)
+}
+
+// Restores the task with the given ID from the trash on behalf of the given
+// actor.
+func (r *TaskRepository) Restore(ctx context.Context, id string, actor string) error {
+	if err := model.ValidateID(id); err != nil {
+		return err
+	}
+
+	if err := model.ValidateActor(actor); err != nil {
+		return err
+	}
+
+	res := r.conn(ctx).Model(&model.Task{}).Where("id = ? AND deleted_at IS NOT NULL", id).UpdateColumn("deleted_at", nil)
+	if res.Error != nil {
+		return fmt.Errorf("Failed to restore task: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return errors.NewExternalError("Task not found in trash.")
+	}
+
+	return r.recordHistory(ctx, id, model.HistoryRestored, actor, nil)
+}
+
+// Permanently deletes all tasks moved to the trash before the given time and
+// returns how many were removed. The content of their attachments is only
+// deleted once the transaction removing them is committed.
+func (r *TaskRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	var affected int64
+
+	err := r.Transaction(ctx, func(ctx context.Context) error {
+		res := r.conn(ctx).Exec("DELETE FROM task_tags WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?)", before.UTC())
+		if res.Error != nil {
+			return fmt.Errorf("Failed to purge task tags: %w", res.Error)
+		}
+
+		attachments, err := r.purgeAttachments(ctx, before)
+		if err != nil {
+			return err
+		}
+
+		if err := r.afterCommit(ctx, func() error {
+			for _, id := range attachments {
+				if err := r.blobs.Delete(id); err != nil {
+					return fmt.Errorf("Failed to purge attachment content: %w", err)
+				}
+			}
+
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		res = r.conn(ctx).Exec("DELETE FROM task_history WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?)", before.UTC())
+		if res.Error != nil {
+			return fmt.Errorf("Failed to purge task history: %w", res.Error)
+		}
+
+		res = r.conn(ctx).Exec("DELETE FROM comments WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?)", before.UTC())
+		if res.Error != nil {
+			return fmt.Errorf("Failed to purge task comments: %w", res.Error)
+		}
+
+		res = r.conn(ctx).Exec("DELETE FROM task_dependencies WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?) OR blocker_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?)", before.UTC(), before.UTC())
+		if res.Error != nil {
+			return fmt.Errorf("Failed to purge task dependencies: %w", res.Error)
+		}
+
+		res = r.conn(ctx).Exec("UPDATE tasks SET parent_id = NULL WHERE parent_id IN (SELECT id FROM (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?) AS purged)", before.UTC())
+		if res.Error != nil {
+			return fmt.Errorf("Failed to detach subtasks of purged tasks: %w", res.Error)
+		}
+
+		res = r.conn(ctx).Where("deleted_at IS NOT NULL AND deleted_at < ?", before.UTC()).Delete(&model.Task{})
+		if res.Error != nil {
+			return fmt.Errorf("Failed to purge tasks: %w", res.Error)
+		}
+
+		affected = res.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return affected, nil
+}
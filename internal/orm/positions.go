@@ -0,0 +1,104 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/mtbuzato/go-challenge/internal/model"
+	"gorm.io/gorm"
+)
+
+// Moves the task with the given ID in the manual order, right after one task,
+// right before another, or between both. Only the moved task is rewritten.
+func (r *TaskRepository) ReorderTask(ctx context.Context, id string, afterID *string, beforeID *string) error {
+	if err := model.ValidateNeighbors(id, afterID, beforeID); err != nil {
+		return err
+	}
+
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	lower, upper := "", ""
+
+	if afterID != nil {
+		after, err := r.getNeighbor(ctx, *afterID)
+		if err != nil {
+			return err
+		}
+
+		lower = after.Position
+	}
+
+	if beforeID != nil {
+		before, err := r.getNeighbor(ctx, *beforeID)
+		if err != nil {
+			return err
+		}
+
+		upper = before.Position
+	}
+
+	var err error
+	if beforeID == nil {
+		upper, err = r.adjacentPosition(ctx, "position > ? AND id <> ?", "position", lower, id)
+	} else if afterID == nil {
+		lower, err = r.adjacentPosition(ctx, "position < ? AND id <> ?", "position DESC", upper, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	position, err := model.PositionBetween(lower, upper)
+	if err != nil {
+		return err
+	}
+
+	res := r.conn(ctx).Model(&model.Task{}).Where("id = ? AND deleted_at IS NULL", id).UpdateColumns(map[string]interface{}{
+		"position":   position,
+		"updated_at": model.Now(),
+		"version":    gorm.Expr("version + 1"),
+	})
+	if res.Error != nil {
+		return fmt.Errorf("Failed to reorder task: %w", res.Error)
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) getNeighbor(ctx context.Context, id string) (model.Task, error) {
+	task, err := r.GetByID(ctx, id)
+	if err != nil && errors.IsExternal(err) {
+		return model.Task{}, errors.NewExternalError("Neighbor task not found.")
+	}
+
+	return task, err
+}
+
+// Returns the position of the first task matching the given condition in the
+// given order, or an empty position when there is none. Tasks in the trash
+// keep their positions, so they are taken into account to avoid sharing them.
+func (r *TaskRepository) adjacentPosition(ctx context.Context, condition string, order string, args ...interface{}) (string, error) {
+	var position string
+	err := r.conn(ctx).Model(&model.Task{}).Select("position").Where(condition, args...).Order(order).Limit(1).Row().Scan(&position)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("Failed to get adjacent position: %w", err)
+	}
+
+	return position, nil
+}
+
+// Returns the position after all tasks, where new tasks are placed.
+func (r *TaskRepository) nextPosition(ctx context.Context) (string, error) {
+	var last sql.NullString
+	if err := r.conn(ctx).Model(&model.Task{}).Select("MAX(position)").Row().Scan(&last); err != nil {
+		return "", fmt.Errorf("Failed to get last position: %w", err)
+	}
+
+	return model.PositionBetween(last.String, "")
+}
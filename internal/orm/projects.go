@@ -0,0 +1,135 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/mtbuzato/go-challenge/internal/model"
+	"gorm.io/gorm"
+)
+
+// Lists all projects ordered by name.
+func (r *TaskRepository) ListProjects(ctx context.Context) ([]model.Project, error) {
+	projects := []model.Project{}
+	res := r.conn(ctx).Order("name, id").Find(&projects)
+	if res.Error != nil {
+		return nil, fmt.Errorf("Failed to query projects: %w", res.Error)
+	}
+
+	return projects, nil
+}
+
+// Gets a project by ID and returns it.
+func (r *TaskRepository) GetProject(ctx context.Context, id string) (model.Project, error) {
+	if err := model.ValidateProjectID(id); err != nil {
+		return model.Project{}, err
+	}
+
+	var project model.Project
+	res := r.conn(ctx).Limit(1).Find(&project, "id = ?", id)
+	if res.Error != nil {
+		return model.Project{}, fmt.Errorf("Failed to get project by ID: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return model.Project{}, errors.NewExternalError("Project not found.")
+	}
+
+	return project, nil
+}
+
+// Creates a new project from the given one and returns it. The ID and
+// timestamps are assigned by the repository.
+func (r *TaskRepository) CreateProject(ctx context.Context, project model.Project) (model.Project, error) {
+	project.Init()
+
+	if err := project.Validate(); err != nil {
+		return model.Project{}, err
+	}
+
+	res := r.conn(ctx).Create(&project)
+	if res.Error != nil {
+		return model.Project{}, fmt.Errorf("Failed to create project: %w", res.Error)
+	}
+
+	return project, nil
+}
+
+// Renames the given project.
+func (r *TaskRepository) UpdateProject(ctx context.Context, project model.Project) error {
+	if err := project.Validate(); err != nil {
+		return err
+	}
+
+	res := r.conn(ctx).Model(&model.Project{}).Where("id = ?", project.ID).Updates(map[string]interface{}{
+		"name":       project.Name,
+		"updated_at": model.Now(),
+	})
+	if res.Error != nil {
+		return fmt.Errorf("Failed to update project: %w", res.Error)
+	}
+
+	return nil
+}
+
+// Deletes the project with the given ID. Its tasks are kept and no longer
+// belong to any project.
+func (r *TaskRepository) DeleteProject(ctx context.Context, id string) error {
+	if err := model.ValidateProjectID(id); err != nil {
+		return err
+	}
+
+	res := r.conn(ctx).Model(&model.Task{}).Where("project_id = ?", id).UpdateColumn("project_id", nil)
+	if res.Error != nil {
+		return fmt.Errorf("Failed to remove tasks from project: %w", res.Error)
+	}
+
+	res = r.conn(ctx).Delete(&model.Project{}, "id = ?", id)
+	if res.Error != nil {
+		return fmt.Errorf("Failed to delete project: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return errors.NewExternalError("Project not found.")
+	}
+
+	return nil
+}
+
+// Moves the task with the given ID to the project with the given ID, or out
+// of its project when the project ID is nil, on behalf of the given actor.
+func (r *TaskRepository) MoveTask(ctx context.Context, id string, projectID *string, actor string) error {
+	if err := model.ValidateActor(actor); err != nil {
+		return err
+	}
+
+	if projectID != nil {
+		if _, err := r.GetProject(ctx, *projectID); err != nil {
+			return err
+		}
+	}
+
+	current, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	res := r.conn(ctx).Model(&model.Task{}).Where("id = ? AND deleted_at IS NULL", id).UpdateColumns(map[string]interface{}{
+		"project_id": projectID,
+		"updated_at": model.Now(),
+		"version":    gorm.Expr("version + 1"),
+	})
+	if res.Error != nil {
+		return fmt.Errorf("Failed to move task: %w", res.Error)
+	}
+
+	moved := current
+	moved.ProjectID = projectID
+
+	if changes := model.DiffTasks(current, moved); len(changes) > 0 {
+		return r.recordHistory(ctx, id, model.HistoryUpdated, actor, changes)
+	}
+
+	return nil
+}
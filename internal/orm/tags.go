@@ -0,0 +1,97 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+// MySQL error returned when a row with a duplicate key is inserted.
+const errDuplicateEntry = 1062
+
+// Row of the join table between tasks and tags.
+type taskTag struct {
+	TaskID string `gorm:"primaryKey"`
+	TagID  string `gorm:"primaryKey"`
+}
+
+// Lists all tags ordered by name.
+func (r *TaskRepository) ListTags(ctx context.Context) ([]model.Tag, error) {
+	tags := []model.Tag{}
+	res := r.conn(ctx).Order("name").Find(&tags)
+	if res.Error != nil {
+		return nil, fmt.Errorf("Failed to query tags: %w", res.Error)
+	}
+
+	return tags, nil
+}
+
+// Lists the tags attached to the task with the given ID, ordered by name.
+func (r *TaskRepository) ListTaskTags(ctx context.Context, id string) ([]model.Tag, error) {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	tags := []model.Tag{}
+	res := r.conn(ctx).Joins("JOIN task_tags ON task_tags.tag_id = tags.id").Where("task_tags.task_id = ?", id).Order("tags.name").Find(&tags)
+	if res.Error != nil {
+		return nil, fmt.Errorf("Failed to query task tags: %w", res.Error)
+	}
+
+	return tags, nil
+}
+
+// Attaches the tag with the given name to the task with the given ID and
+// returns it. The tag is created if it does not exist yet, and attaching a tag
+// the task already has does nothing.
+func (r *TaskRepository) AttachTag(ctx context.Context, id string, name string) (model.Tag, error) {
+	tag, err := model.NewTag(name)
+	if err != nil {
+		return model.Tag{}, err
+	}
+
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return model.Tag{}, err
+	}
+
+	res := r.conn(ctx).Where(model.Tag{Name: tag.Name}).Attrs(model.Tag{ID: tag.ID}).FirstOrCreate(&tag)
+	if res.Error != nil {
+		return model.Tag{}, fmt.Errorf("Failed to get or create tag: %w", res.Error)
+	}
+
+	res = r.conn(ctx).Create(&taskTag{TaskID: id, TagID: tag.ID})
+	if res.Error != nil {
+		if mysqlErr, ok := res.Error.(*gomysql.MySQLError); !ok || mysqlErr.Number != errDuplicateEntry {
+			return model.Tag{}, fmt.Errorf("Failed to attach tag: %w", res.Error)
+		}
+	}
+
+	return tag, nil
+}
+
+// Detaches the tag with the given name from the task with the given ID.
+func (r *TaskRepository) DetachTag(ctx context.Context, id string, name string) error {
+	name = model.NormalizeTagName(name)
+
+	if err := model.ValidateTagName(name); err != nil {
+		return err
+	}
+
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	res := r.conn(ctx).Where("task_id = ? AND tag_id IN (?)", id, r.conn(ctx).Model(&model.Tag{}).Select("id").Where("name = ?", name)).Delete(&taskTag{})
+	if res.Error != nil {
+		return fmt.Errorf("Failed to detach tag: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		return errors.NewExternalError("Tag not found on task.")
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package orm
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// A transaction carried by a context, along with the side effects outside the
+// database, such as on attachment content, that wait for it to end.
+type transaction struct {
+	tx         *gorm.DB
+	onCommit   []func() error
+	onRollback []func()
+}
+
+func (t *transaction) rolledBack() {
+	for _, fn := range t.onRollback {
+		fn()
+	}
+}
+
+// Runs every function waiting for the commit, returning the first error.
+func (t *transaction) committed() error {
+	var first error
+	for _, fn := range t.onCommit {
+		if err := fn(); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
+
+// Returns the transaction carried by the given context, or the database when
+// there is none, bound to the context.
+func (r *TaskRepository) conn(ctx context.Context) *gorm.DB {
+	if t, ok := ctx.Value(txKey{}).(*transaction); ok {
+		return t.tx.WithContext(ctx)
+	}
+
+	return r.gormDB.WithContext(ctx)
+}
+
+// Runs the given function once the transaction carried by the context is
+// committed, or right away when there is none. Its error is returned by
+// Transaction, the changes to the database being kept.
+func (r *TaskRepository) afterCommit(ctx context.Context, fn func() error) error {
+	if t, ok := ctx.Value(txKey{}).(*transaction); ok {
+		t.onCommit = append(t.onCommit, fn)
+		return nil
+	}
+
+	return fn()
+}
+
+// Runs the given function if the transaction carried by the context is rolled
+// back. Without a transaction there is nothing to roll back.
+func (r *TaskRepository) afterRollback(ctx context.Context, fn func()) {
+	if t, ok := ctx.Value(txKey{}).(*transaction); ok {
+		t.onRollback = append(t.onRollback, fn)
+	}
+}
+
+// Runs the given function in a transaction. Repository calls made with the
+// context passed to it are part of the transaction, which is committed when the
+// function succeeds and rolled back when it returns an error or panics. When
+// the context already carries a transaction the function simply joins it.
+func (r *TaskRepository) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*transaction); ok {
+		return fn(ctx)
+	}
+
+	t := &transaction{}
+
+	defer func() {
+		if p := recover(); p != nil {
+			t.rolledBack()
+			panic(p)
+		}
+	}()
+
+	err := r.gormDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		t.tx = tx
+		return fn(context.WithValue(ctx, txKey{}, t))
+	})
+	if err != nil {
+		t.rolledBack()
+		return err
+	}
+
+	return t.committed()
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+const attachmentColumns = "id, task_id, name, content_type, size, created_at"
+
+func scanAttachment(row scanner) (model.Attachment, error) {
+	var attachment model.Attachment
+
+	if err := row.Scan(&attachment.ID, &attachment.TaskID, &attachment.Name, &attachment.ContentType, &attachment.Size, &attachment.CreatedAt); err != nil {
+		return model.Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+// Lists the files attached to the task with the given ID, oldest first.
+func (r *TaskRepository) ListAttachments(ctx context.Context, id string) ([]model.Attachment, error) {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, "SELECT "+attachmentColumns+" FROM attachments WHERE task_id = ? ORDER BY created_at, id", id)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query attachments: %w", err)
+	}
+
+	defer rows.Close()
+
+	attachments := []model.Attachment{}
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to scan attachment: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// Gets the attachment with the given ID on the task with the given ID.
+func (r *TaskRepository) GetAttachment(ctx context.Context, id string, attachmentID string) (model.Attachment, error) {
+	if err := model.ValidateAttachmentID(attachmentID); err != nil {
+		return model.Attachment{}, err
+	}
+
+	// Attachments of tasks in the trash are hidden along with them.
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return model.Attachment{}, err
+	}
+
+	attachment, err := scanAttachment(r.conn(ctx).QueryRowContext(ctx, "SELECT "+attachmentColumns+" FROM attachments WHERE id = ? AND task_id = ?", attachmentID, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.Attachment{}, errors.NewExternalError("Attachment not found.")
+		}
+
+		return model.Attachment{}, fmt.Errorf("Failed to get attachment by ID: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// Gets the attachment with the given ID on the task with the given ID along
+// with its content, which the caller must close.
+func (r *TaskRepository) OpenAttachment(ctx context.Context, id string, attachmentID string) (model.Attachment, io.ReadCloser, error) {
+	attachment, err := r.GetAttachment(ctx, id, attachmentID)
+	if err != nil {
+		return model.Attachment{}, nil, err
+	}
+
+	content, err := r.blobs.Open(attachment.ID)
+	if err != nil {
+		return model.Attachment{}, nil, fmt.Errorf("Failed to open attachment: %w", err)
+	}
+
+	return attachment, content, nil
+}
+
+// Attaches a file with the given content to the task the attachment
+// references and returns it. The ID, size and creation time are assigned by
+// the repository, and content over the size limit is rejected. The content is
+// removed again when the transaction the attachment is created in is rolled
+// back.
+func (r *TaskRepository) CreateAttachment(ctx context.Context, attachment model.Attachment, content io.Reader) (model.Attachment, error) {
+	attachment.Init()
+	attachment.Size = 0
+
+	if err := attachment.Validate(); err != nil {
+		return model.Attachment{}, err
+	}
+
+	if _, err := r.GetByID(ctx, attachment.TaskID); err != nil {
+		return model.Attachment{}, err
+	}
+
+	size, err := r.blobs.Put(attachment.ID, io.LimitReader(content, model.MaxAttachmentSize+1))
+	if err != nil {
+		return model.Attachment{}, fmt.Errorf("Failed to store attachment: %w", err)
+	}
+
+	attachment.Size = size
+
+	if err := model.ValidateAttachmentSize(attachment.Size); err != nil {
+		r.blobs.Delete(attachment.ID)
+		return model.Attachment{}, err
+	}
+
+	if _, err := r.conn(ctx).ExecContext(
+		ctx,
+		"INSERT INTO attachments (id, task_id, name, content_type, size, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		attachment.ID, attachment.TaskID, attachment.Name, attachment.ContentType, attachment.Size, attachment.CreatedAt,
+	); err != nil {
+		r.blobs.Delete(attachment.ID)
+		return model.Attachment{}, fmt.Errorf("Failed to create attachment: %w", err)
+	}
+
+	r.afterRollback(ctx, func() {
+		r.blobs.Delete(attachment.ID)
+	})
+
+	return attachment, nil
+}
+
+// Deletes the attachment with the given ID from the task with the given ID,
+// along with its content once the deletion is committed.
+func (r *TaskRepository) DeleteAttachment(ctx context.Context, id string, attachmentID string) error {
+	if err := model.ValidateAttachmentID(attachmentID); err != nil {
+		return err
+	}
+
+	// Attachments of tasks in the trash are hidden along with them.
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM attachments WHERE id = ? AND task_id = ?", attachmentID, id)
+	if err != nil {
+		return fmt.Errorf("Failed to delete attachment: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to delete attachment: %w", err)
+	}
+
+	if affected == 0 {
+		return errors.NewExternalError("Attachment not found.")
+	}
+
+	// The content is kept until the deletion is committed, as it cannot be
+	// brought back when the transaction is rolled back.
+	return r.afterCommit(ctx, func() error {
+		if err := r.blobs.Delete(attachmentID); err != nil {
+			return fmt.Errorf("Failed to delete attachment content: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// Deletes the attachments of the tasks purged before the given time and
+// returns their IDs, leaving their content to be deleted by the caller.
+func (r *TaskRepository) purgeAttachments(ctx context.Context, before time.Time) ([]string, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, "SELECT id FROM attachments WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?)", before.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query purged attachments: %w", err)
+	}
+
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("Failed to scan purged attachment: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to scan purged attachment: %w", err)
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	if _, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM attachments WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?)", before.UTC()); err != nil {
+		return nil, fmt.Errorf("Failed to purge attachments: %w", err)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,394 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+const testAttachmentID = "cl09rb83d000009l13y5n5at1"
+
+var attachmentColumnNames = []string{"id", "task_id", "name", "content_type", "size", "created_at"}
+
+// Blob store keeping contents in memory.
+type memoryStore map[string][]byte
+
+func (s memoryStore) Put(key string, content io.Reader) (int64, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return 0, err
+	}
+
+	s[key] = data
+
+	return int64(len(data)), nil
+}
+
+func (s memoryStore) Open(key string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(s[key])), nil
+}
+
+func (s memoryStore) Delete(key string) error {
+	delete(s, key)
+	return nil
+}
+
+func attachmentRows(mock sqlmock.Sqlmock, attachments ...model.Attachment) *sqlmock.Rows {
+	rows := mock.NewRows(attachmentColumnNames)
+	for _, attachment := range attachments {
+		rows.AddRow(attachment.ID, attachment.TaskID, attachment.Name, attachment.ContentType, attachment.Size, attachment.CreatedAt)
+	}
+
+	return rows
+}
+
+func TestListAttachments(t *testing.T) {
+	createdAt := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	attachments := []model.Attachment{
+		{ID: "1", TaskID: testTaskID, Name: "screenshot.png", ContentType: "image/png", Size: 1024, CreatedAt: createdAt},
+		{ID: "2", TaskID: testTaskID, Name: "server.log", ContentType: "text/plain", Size: 512, CreatedAt: createdAt},
+	}
+
+	assert, db, mock := beforeAll(t)
+	defer db.Close()
+
+	expectTask(mock, true)
+	mock.ExpectQuery("SELECT (.+) FROM attachments WHERE task_id = \\? ORDER BY created_at, id").
+		WithArgs(testTaskID).
+		WillReturnRows(attachmentRows(mock, attachments...))
+
+	repo := NewTaskRepository(db, nil)
+	actual, err := repo.ListAttachments(context.Background(), testTaskID)
+
+	assert.NoError(err)
+	assert.Equal(attachments, actual)
+
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestOpenAttachment(t *testing.T) {
+	attachment := model.Attachment{ID: testAttachmentID, TaskID: testTaskID, Name: "server.log", ContentType: "text/plain", Size: 5}
+
+	tests := map[string]struct {
+		shouldError bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"existing": {
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				mock.ExpectQuery("SELECT (.+) FROM attachments WHERE id = \\? AND task_id = \\?").
+					WithArgs(testAttachmentID, testTaskID).
+					WillReturnRows(attachmentRows(mock, attachment))
+			},
+		},
+		"task_in_trash": {
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, false)
+			},
+		},
+		"non_existing": {
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				mock.ExpectQuery("SELECT (.+) FROM attachments WHERE id = \\? AND task_id = \\?").
+					WithArgs(testAttachmentID, testTaskID).
+					WillReturnRows(attachmentRows(mock))
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, memoryStore{testAttachmentID: []byte("hello")})
+			actual, content, err := repo.OpenAttachment(context.Background(), testTaskID, testAttachmentID)
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				assert.Equal(attachment, actual)
+
+				data, err := io.ReadAll(content)
+				assert.NoError(err)
+				assert.Equal("hello", string(data))
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCreateAttachment(t *testing.T) {
+	tests := map[string]struct {
+		attachment  model.Attachment
+		content     string
+		shouldError bool
+		stored      bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"valid": {
+			attachment: model.Attachment{TaskID: testTaskID, Name: "server.log", ContentType: "text/plain; charset=utf-8"},
+			content:    "hello",
+			stored:     true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				mock.ExpectExec("INSERT INTO attachments").
+					WithArgs(CUID{}, testTaskID, "server.log", "text/plain", int64(5), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"type_not_allowed": {
+			attachment:  model.Attachment{TaskID: testTaskID, Name: "script.sh", ContentType: "application/x-sh"},
+			content:     "echo",
+			shouldError: true,
+			sql:         func(mock sqlmock.Sqlmock) {},
+		},
+		"too_large": {
+			attachment:  model.Attachment{TaskID: testTaskID, Name: "server.log", ContentType: "text/plain"},
+			content:     strings.Repeat("a", model.MaxAttachmentSize+1),
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+			},
+		},
+		"task_not_found": {
+			attachment:  model.Attachment{TaskID: testTaskID, Name: "server.log", ContentType: "text/plain"},
+			content:     "hello",
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, false)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			blobs := memoryStore{}
+			repo := NewTaskRepository(db, blobs)
+			attachment, err := repo.CreateAttachment(context.Background(), test.attachment, strings.NewReader(test.content))
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				assert.Equal(int64(len(test.content)), attachment.Size)
+				assert.NoError(model.ValidateAttachmentID(attachment.ID))
+				assert.Equal(test.content, string(blobs[attachment.ID]))
+			}
+
+			if !test.stored {
+				assert.Empty(blobs)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	tests := map[string]struct {
+		shouldError bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"existing": {
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				mock.ExpectExec("DELETE FROM attachments WHERE id = \\? AND task_id = \\?").
+					WithArgs(testAttachmentID, testTaskID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		"task_in_trash": {
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, false)
+			},
+		},
+		"non_existing": {
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				mock.ExpectExec("DELETE FROM attachments WHERE id = \\? AND task_id = \\?").
+					WithArgs(testAttachmentID, testTaskID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			blobs := memoryStore{testAttachmentID: []byte("hello")}
+			repo := NewTaskRepository(db, blobs)
+			err := repo.DeleteAttachment(context.Background(), testTaskID, testAttachmentID)
+
+			if test.shouldError {
+				assert.Error(err)
+				assert.Contains(blobs, testAttachmentID)
+			} else {
+				assert.NoError(err)
+				assert.NotContains(blobs, testAttachmentID)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAttachmentsInTransaction(t *testing.T) {
+	attachment := model.Attachment{TaskID: testTaskID, Name: "server.log", ContentType: "text/plain"}
+
+	tests := map[string]struct {
+		change   func(ctx context.Context, repo *TaskRepository) error
+		existing bool
+		rollback bool
+		stored   bool
+		sql      func(mock sqlmock.Sqlmock)
+	}{
+		"rolled_back_creation_removes_content": {
+			change: func(ctx context.Context, repo *TaskRepository) error {
+				_, err := repo.CreateAttachment(ctx, attachment, strings.NewReader("hello"))
+				return err
+			},
+			rollback: true,
+			stored:   false,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				mock.ExpectExec("INSERT INTO attachments").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"committed_creation_keeps_content": {
+			change: func(ctx context.Context, repo *TaskRepository) error {
+				_, err := repo.CreateAttachment(ctx, attachment, strings.NewReader("hello"))
+				return err
+			},
+			stored: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				mock.ExpectExec("INSERT INTO attachments").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"rolled_back_deletion_keeps_content": {
+			change: func(ctx context.Context, repo *TaskRepository) error {
+				return repo.DeleteAttachment(ctx, testTaskID, testAttachmentID)
+			},
+			existing: true,
+			rollback: true,
+			stored:   true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				mock.ExpectExec("DELETE FROM attachments WHERE id = \\? AND task_id = \\?").
+					WithArgs(testAttachmentID, testTaskID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		"committed_deletion_removes_content": {
+			change: func(ctx context.Context, repo *TaskRepository) error {
+				return repo.DeleteAttachment(ctx, testTaskID, testAttachmentID)
+			},
+			existing: true,
+			stored:   false,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				mock.ExpectExec("DELETE FROM attachments WHERE id = \\? AND task_id = \\?").
+					WithArgs(testAttachmentID, testTaskID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			test.sql(mock)
+			if test.rollback {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectCommit()
+			}
+
+			blobs := memoryStore{}
+			if test.existing {
+				blobs[testAttachmentID] = []byte("hello")
+			}
+
+			repo := NewTaskRepository(db, blobs)
+			err := repo.Transaction(context.Background(), func(ctx context.Context) error {
+				if err := test.change(ctx, repo); err != nil {
+					return err
+				}
+
+				if test.rollback {
+					return sql.ErrConnDone
+				}
+
+				return nil
+			})
+
+			if test.rollback {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			if test.stored {
+				assert.Len(blobs, 1)
+			} else {
+				assert.Empty(blobs)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPurgeAttachments(t *testing.T) {
+	before := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	assert, db, mock := beforeAll(t)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id FROM attachments WHERE task_id IN").
+		WithArgs(before).
+		WillReturnRows(mock.NewRows([]string{"id"}).AddRow(testAttachmentID))
+	mock.ExpectExec("DELETE FROM attachments WHERE task_id IN").
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	blobs := memoryStore{testAttachmentID: []byte("hello")}
+	repo := NewTaskRepository(db, blobs)
+	ids, err := repo.purgeAttachments(context.Background(), before)
+
+	assert.NoError(err)
+	assert.Equal([]string{testAttachmentID}, ids)
+	assert.Len(blobs, 1)
+
+	assert.NoError(mock.ExpectationsWereMet())
+}
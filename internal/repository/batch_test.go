@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+func expectCreatedTasks(mock sqlmock.Sqlmock, names ...string) {
+	args := []driver.Value{}
+	history := []driver.Value{}
+	position := "V"
+
+	for i, name := range names {
+		if i > 0 {
+			position, _ = model.PositionBetween(position, "")
+		}
+
+		args = append(args, CUID{}, name, "", false, model.StatusTodo, position, model.PriorityNone, nil, nil, false, "", sqlmock.AnyArg(), sqlmock.AnyArg(), nil, nil, 1, nil)
+		history = append(history, CUID{}, CUID{}, model.HistoryCreated, testActor, sqlmock.AnyArg(), sqlmock.AnyArg())
+	}
+
+	expectLastPosition(mock, nil)
+	mock.ExpectExec("INSERT INTO tasks (.+) VALUES \\(.+\\), \\(.+\\)").
+		WithArgs(args...).
+		WillReturnResult(sqlmock.NewResult(0, int64(len(names))))
+	mock.ExpectExec("INSERT INTO task_history (.+) VALUES \\(.+\\), \\(.+\\)").
+		WithArgs(history...).
+		WillReturnResult(sqlmock.NewResult(0, int64(len(names))))
+}
+
+func expectExistingTasks(mock sqlmock.Sqlmock, ids []driver.Value, existing ...string) {
+	rows := mock.NewRows([]string{"id"})
+	for _, id := range existing {
+		rows.AddRow(id)
+	}
+
+	mock.ExpectQuery("SELECT id FROM tasks WHERE id IN \\((.+)\\) AND deleted_at IS NULL").
+		WithArgs(ids...).
+		WillReturnRows(rows)
+}
+
+func TestBatch(t *testing.T) {
+	completed := model.Task{ID: testTaskID, Name: "Task 1", Completed: true, Status: model.StatusDone, Priority: model.PriorityHigh, Version: 2}
+	rename := model.BatchOperation{
+		Action: model.BatchUpdate,
+		ID:     testTaskID,
+		Patch:  model.TaskPatch{Fields: []string{"name"}, Task: model.Task{Name: "Task 1 Updated"}},
+	}
+
+	expectRename := func(mock sqlmock.Sqlmock) {
+		expectTaskRow(mock, completed)
+		expectTaskRow(mock, completed)
+		mock.ExpectExec("UPDATE tasks SET name = \\?").
+			WithArgs("Task 1 Updated", "", true, model.StatusDone, true, sqlmock.AnyArg(), model.PriorityHigh, nil, false, "", nil, nil, sqlmock.AnyArg(), testTaskID, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectHistory(mock, testTaskID, model.HistoryUpdated, sqlmock.AnyArg())
+	}
+
+	tests := map[string]struct {
+		operations  []model.BatchOperation
+		atomic      bool
+		nested      bool
+		expected    model.BatchResponse
+		shouldError bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"applies_every_operation": {
+			operations: []model.BatchOperation{
+				{Action: model.BatchCreate, Task: model.Task{Name: "Task 2"}},
+				rename,
+				{Action: model.BatchCreate, Task: model.Task{Name: "Task 3"}},
+				{Action: model.BatchDelete, ID: testSubtaskID},
+				{Action: model.BatchDelete, ID: testSubsubtaskID},
+			},
+			expected: model.BatchResponse{
+				Applied: true,
+				Results: []model.BatchResult{{}, {ID: testTaskID}, {}, {ID: testSubtaskID}, {ID: testSubsubtaskID}},
+			},
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectRename(mock)
+				expectExistingTasks(mock, []driver.Value{testSubtaskID, testSubsubtaskID}, testSubtaskID, testSubsubtaskID)
+				expectCreatedTasks(mock, "Task 2", "Task 3")
+				mock.ExpectExec("UPDATE tasks SET deleted_at = \\? WHERE id IN \\(\\?, \\?\\) AND deleted_at IS NULL").
+					WithArgs(sqlmock.AnyArg(), testSubtaskID, testSubsubtaskID).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("INSERT INTO task_history (.+) VALUES \\(.+\\), \\(.+\\)").
+					WithArgs(
+						CUID{}, testSubtaskID, model.HistoryDeleted, testActor, []byte(`[]`), sqlmock.AnyArg(),
+						CUID{}, testSubsubtaskID, model.HistoryDeleted, testActor, []byte(`[]`), sqlmock.AnyArg(),
+					).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+		},
+		"reports_failed_operations": {
+			operations: []model.BatchOperation{
+				{Action: model.BatchCreate, Task: model.Task{Name: ""}},
+				rename,
+				{Action: model.BatchDelete, ID: testSubtaskID},
+				{Action: model.BatchDelete, ID: "invalid"},
+			},
+			expected: model.BatchResponse{
+				Applied: true,
+				Results: []model.BatchResult{
+					{Error: "Invalid task name."},
+					{ID: testTaskID},
+					{ID: testSubtaskID, Error: "Task not found."},
+					{ID: "invalid", Error: "Invalid task ID."},
+				},
+			},
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectRename(mock)
+				expectExistingTasks(mock, []driver.Value{testSubtaskID})
+				mock.ExpectCommit()
+			},
+		},
+		"atomic_rolls_back_on_failure": {
+			operations: []model.BatchOperation{
+				rename,
+				{Action: model.BatchDelete, ID: testSubtaskID},
+			},
+			atomic: true,
+			expected: model.BatchResponse{
+				Applied: false,
+				Results: []model.BatchResult{{ID: testTaskID}, {ID: testSubtaskID, Error: "Task not found."}},
+			},
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectRename(mock)
+				expectExistingTasks(mock, []driver.Value{testSubtaskID})
+				mock.ExpectRollback()
+			},
+		},
+		"atomic_rolls_back_enclosing_transaction": {
+			operations: []model.BatchOperation{
+				rename,
+				{Action: model.BatchDelete, ID: testSubtaskID},
+			},
+			atomic: true,
+			nested: true,
+			expected: model.BatchResponse{
+				Applied: false,
+				Results: []model.BatchResult{{ID: testTaskID}, {ID: testSubtaskID, Error: "Task not found."}},
+			},
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectRename(mock)
+				expectExistingTasks(mock, []driver.Value{testSubtaskID})
+				mock.ExpectRollback()
+			},
+		},
+		"empty": {
+			operations:  []model.BatchOperation{},
+			shouldError: true,
+			sql:         func(mock sqlmock.Sqlmock) {},
+		},
+		"invalid_action": {
+			operations:  []model.BatchOperation{{Action: "archive", ID: testTaskID}},
+			shouldError: true,
+			sql:         func(mock sqlmock.Sqlmock) {},
+		},
+		"database_error": {
+			operations:  []model.BatchOperation{{Action: model.BatchDelete, ID: testSubtaskID}},
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT id FROM tasks WHERE id IN").
+					WithArgs(testSubtaskID).
+					WillReturnError(errors.New("connection lost"))
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, nil)
+
+			var response model.BatchResponse
+			var err error
+
+			batch := func(ctx context.Context) error {
+				response, err = repo.Batch(ctx, test.operations, test.atomic, testActor)
+				return err
+			}
+
+			if test.nested {
+				// The enclosing transaction is rolled back, as the batch fails.
+				assert.Error(repo.Transaction(context.Background(), batch))
+			} else {
+				batch(context.Background())
+			}
+
+			if notApplied, ok := model.NotAppliedBatch(err); ok {
+				assert.Empty(response)
+				response, err = notApplied, nil
+			}
+
+			if test.shouldError {
+				assert.Error(err)
+				assert.Empty(response)
+			} else {
+				assert.NoError(err)
+				assert.Equal(test.expected.Applied, response.Applied)
+				assert.Len(response.Results, len(test.expected.Results))
+
+				for i, result := range response.Results {
+					if test.operations[i].Action == model.BatchCreate && result.Error == "" {
+						result.ID = ""
+					}
+
+					assert.Equal(test.expected.Results[i], result)
+				}
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
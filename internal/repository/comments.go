@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+const commentColumns = "id, task_id, author, body, created_at, updated_at"
+
+func scanComment(row scanner) (model.Comment, error) {
+	var comment model.Comment
+
+	if err := row.Scan(&comment.ID, &comment.TaskID, &comment.Author, &comment.Body, &comment.CreatedAt, &comment.UpdatedAt); err != nil {
+		return model.Comment{}, err
+	}
+
+	return comment, nil
+}
+
+// Lists the comments on the task with the given ID, oldest first.
+func (r *TaskRepository) ListComments(ctx context.Context, id string) ([]model.Comment, error) {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, "SELECT "+commentColumns+" FROM comments WHERE task_id = ? ORDER BY created_at, id", id)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query comments: %w", err)
+	}
+
+	defer rows.Close()
+
+	comments := []model.Comment{}
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to scan comment: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to scan comment: %w", err)
+	}
+
+	return comments, nil
+}
+
+// Creates a new comment on the task it references and returns it. The ID and
+// timestamps are assigned by the repository.
+func (r *TaskRepository) CreateComment(ctx context.Context, comment model.Comment) (model.Comment, error) {
+	comment.Init()
+
+	if err := comment.Validate(); err != nil {
+		return model.Comment{}, err
+	}
+
+	if _, err := r.GetByID(ctx, comment.TaskID); err != nil {
+		return model.Comment{}, err
+	}
+
+	if _, err := r.conn(ctx).ExecContext(
+		ctx,
+		"INSERT INTO comments (id, task_id, author, body, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
+		comment.ID, comment.TaskID, comment.Author, comment.Body, comment.CreatedAt, comment.UpdatedAt,
+	); err != nil {
+		return model.Comment{}, fmt.Errorf("Failed to create comment: %w", err)
+	}
+
+	return comment, nil
+}
+
+// Deletes the comment with the given ID from the task with the given ID.
+func (r *TaskRepository) DeleteComment(ctx context.Context, id string, commentID string) error {
+	if err := model.ValidateID(id); err != nil {
+		return err
+	}
+
+	if err := model.ValidateCommentID(commentID); err != nil {
+		return err
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM comments WHERE id = ? AND task_id = ?", commentID, id)
+	if err != nil {
+		return fmt.Errorf("Failed to delete comment: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to delete comment: %w", err)
+	}
+
+	if affected == 0 {
+		return errors.NewExternalError("Comment not found.")
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+const testCommentID = "cl09rb83d000009l13y5n5cm1"
+
+var commentColumnNames = []string{"id", "task_id", "author", "body", "created_at", "updated_at"}
+
+func commentRows(mock sqlmock.Sqlmock, comments ...model.Comment) *sqlmock.Rows {
+	rows := mock.NewRows(commentColumnNames)
+	for _, comment := range comments {
+		rows.AddRow(comment.ID, comment.TaskID, comment.Author, comment.Body, comment.CreatedAt, comment.UpdatedAt)
+	}
+
+	return rows
+}
+
+func TestListComments(t *testing.T) {
+	createdAt := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	comments := []model.Comment{
+		{ID: "1", TaskID: testTaskID, Author: "Alice", Body: "First", CreatedAt: createdAt, UpdatedAt: createdAt},
+		{ID: "2", TaskID: testTaskID, Author: "Bob", Body: "Second", CreatedAt: createdAt, UpdatedAt: createdAt},
+	}
+
+	tests := map[string]struct {
+		expected    []model.Comment
+		shouldError bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"existing": {
+			expected: comments,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				mock.ExpectQuery("SELECT (.+) FROM comments WHERE task_id = \\? ORDER BY created_at, id").
+					WithArgs(testTaskID).
+					WillReturnRows(commentRows(mock, comments...))
+			},
+		},
+		"task_not_found": {
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, false)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, nil)
+			actual, err := repo.ListComments(context.Background(), testTaskID)
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				assert.Equal(test.expected, actual)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCreateComment(t *testing.T) {
+	tests := map[string]struct {
+		comment     model.Comment
+		shouldError bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"valid": {
+			comment: model.Comment{TaskID: testTaskID, Author: "Alice", Body: "Looks good to me."},
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				mock.ExpectExec("INSERT INTO comments").
+					WithArgs(CUID{}, testTaskID, "Alice", "Looks good to me.", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"empty_body": {
+			comment:     model.Comment{TaskID: testTaskID, Author: "Alice"},
+			shouldError: true,
+			sql:         func(mock sqlmock.Sqlmock) {},
+		},
+		"task_not_found": {
+			comment:     model.Comment{TaskID: testTaskID, Author: "Alice", Body: "Looks good to me."},
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, false)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, nil)
+			comment, err := repo.CreateComment(context.Background(), test.comment)
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				assert.Equal(test.comment.Body, comment.Body)
+				assert.NoError(model.ValidateCommentID(comment.ID))
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeleteComment(t *testing.T) {
+	tests := map[string]struct {
+		commentID   string
+		shouldError bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"existing": {
+			commentID: testCommentID,
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM comments WHERE id = \\? AND task_id = \\?").
+					WithArgs(testCommentID, testTaskID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		"non_existing": {
+			commentID:   testCommentID,
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM comments WHERE id = \\? AND task_id = \\?").
+					WithArgs(testCommentID, testTaskID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		"invalid_id": {
+			commentID:   "1",
+			shouldError: true,
+			sql:         func(mock sqlmock.Sqlmock) {},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, nil)
+			err := repo.DeleteComment(context.Background(), testTaskID, test.commentID)
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
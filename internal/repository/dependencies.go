@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+// Lists the tasks that block the task with the given ID and the tasks it
+// blocks. Tasks in the trash are left out.
+func (r *TaskRepository) ListDependencies(ctx context.Context, id string) (model.TaskDependencies, error) {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return model.TaskDependencies{}, err
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, "SELECT "+taskColumns+" FROM tasks WHERE id IN (SELECT blocker_id FROM task_dependencies WHERE task_id = ?) AND deleted_at IS NULL ORDER BY id", id)
+	if err != nil {
+		return model.TaskDependencies{}, fmt.Errorf("Failed to query blockers: %w", err)
+	}
+
+	blockedBy, err := scanTasks(rows)
+	if err != nil {
+		return model.TaskDependencies{}, err
+	}
+
+	rows, err = r.conn(ctx).QueryContext(ctx, "SELECT "+taskColumns+" FROM tasks WHERE id IN (SELECT task_id FROM task_dependencies WHERE blocker_id = ?) AND deleted_at IS NULL ORDER BY id", id)
+	if err != nil {
+		return model.TaskDependencies{}, fmt.Errorf("Failed to query blocked tasks: %w", err)
+	}
+
+	blocks, err := scanTasks(rows)
+	if err != nil {
+		return model.TaskDependencies{}, err
+	}
+
+	return model.TaskDependencies{BlockedBy: blockedBy, Blocks: blocks}, nil
+}
+
+// Marks the task with the given ID as blocked by another task. Dependencies
+// that would make a task block itself, directly or not, are rejected.
+func (r *TaskRepository) AddDependency(ctx context.Context, id string, blockerID string) error {
+	if err := model.ValidateDependency(id, blockerID, nil); err != nil {
+		return err
+	}
+
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	if _, err := r.GetByID(ctx, blockerID); err != nil {
+		if errors.IsExternal(err) {
+			return errors.NewExternalError("Blocker task not found.")
+		}
+
+		return err
+	}
+
+	blockerIDs, err := r.transitiveBlockerIDs(ctx, blockerID)
+	if err != nil {
+		return err
+	}
+
+	if err := model.ValidateDependency(id, blockerID, blockerIDs); err != nil {
+		return err
+	}
+
+	if _, err := r.conn(ctx).ExecContext(ctx, "INSERT INTO task_dependencies (task_id, blocker_id) VALUES (?, ?)", id, blockerID); err != nil {
+		if mysqlErr, ok := err.(*mysql.MySQLError); !ok || mysqlErr.Number != errDuplicateEntry {
+			return fmt.Errorf("Failed to add dependency: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Removes the dependency of the task with the given ID on another task.
+func (r *TaskRepository) RemoveDependency(ctx context.Context, id string, blockerID string) error {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM task_dependencies WHERE task_id = ? AND blocker_id = ?", id, blockerID)
+	if err != nil {
+		return fmt.Errorf("Failed to remove dependency: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to remove dependency: %w", err)
+	}
+
+	if affected == 0 {
+		return errors.NewExternalError("Dependency not found.")
+	}
+
+	return nil
+}
+
+// Returns the IDs of every task that the task with the given ID is directly or
+// indirectly blocked by.
+func (r *TaskRepository) transitiveBlockerIDs(ctx context.Context, id string) ([]string, error) {
+	blockerIDs := []string{}
+	seen := map[string]bool{id: true}
+
+	for taskIDs := []string{id}; len(taskIDs) > 0; {
+		placeholders := make([]string, len(taskIDs))
+		args := make([]interface{}, len(taskIDs))
+		for i, taskID := range taskIDs {
+			placeholders[i] = "?"
+			args[i] = taskID
+		}
+
+		rows, err := r.conn(ctx).QueryContext(ctx, "SELECT blocker_id FROM task_dependencies WHERE task_id IN ("+strings.Join(placeholders, ", ")+")", args...)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to query blockers: %w", err)
+		}
+
+		taskIDs = nil
+		for rows.Next() {
+			var blockerID string
+			if err := rows.Scan(&blockerID); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("Failed to scan blocker: %w", err)
+			}
+
+			if !seen[blockerID] {
+				seen[blockerID] = true
+				blockerIDs = append(blockerIDs, blockerID)
+				taskIDs = append(taskIDs, blockerID)
+			}
+		}
+
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to scan blocker: %w", err)
+		}
+	}
+
+	return blockerIDs, nil
+}
+
+// Returns how many open tasks block the task with the given ID.
+func (r *TaskRepository) countOpenBlockers(ctx context.Context, id string) (int, error) {
+	var count int
+	if err := r.conn(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM task_dependencies JOIN tasks ON tasks.id = task_dependencies.blocker_id WHERE task_dependencies.task_id = ? AND tasks.completed = ? AND tasks.deleted_at IS NULL", id, false).Scan(&count); err != nil {
+		return 0, fmt.Errorf("Failed to count blockers: %w", err)
+	}
+
+	return count, nil
+}
+
+// Checks that the task with the given ID is not blocked by any open task.
+func (r *TaskRepository) validateUnblocked(ctx context.Context, id string) error {
+	count, err := r.countOpenBlockers(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return errors.NewExternalError("Task is blocked by open tasks.")
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+const testBlockerID = "cl09rb83d000009l13y5n5bl1"
+
+func expectOpenBlockers(mock sqlmock.Sqlmock, id string, count int) {
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM task_dependencies JOIN tasks ON (.+) WHERE task_dependencies.task_id = \\? AND tasks.completed = \\?").
+		WithArgs(id, false).
+		WillReturnRows(mock.NewRows([]string{"count"}).AddRow(count))
+}
+
+func expectBlockerIDs(mock sqlmock.Sqlmock, id string, blockerIDs ...string) {
+	rows := mock.NewRows([]string{"blocker_id"})
+	for _, blockerID := range blockerIDs {
+		rows.AddRow(blockerID)
+	}
+
+	mock.ExpectQuery("SELECT blocker_id FROM task_dependencies WHERE task_id IN \\(\\?\\)").
+		WithArgs(id).
+		WillReturnRows(rows)
+}
+
+func TestListDependencies(t *testing.T) {
+	blocker := model.Task{ID: testBlockerID, Name: "Task 2"}
+
+	assert, db, mock := beforeAll(t)
+	defer db.Close()
+
+	expectTask(mock, true)
+	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE id IN \\(SELECT blocker_id FROM task_dependencies WHERE task_id = \\?\\) AND deleted_at IS NULL").
+		WithArgs(testTaskID).
+		WillReturnRows(taskRows(mock, blocker))
+	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE id IN \\(SELECT task_id FROM task_dependencies WHERE blocker_id = \\?\\) AND deleted_at IS NULL").
+		WithArgs(testTaskID).
+		WillReturnRows(taskRows(mock))
+
+	repo := NewTaskRepository(db, nil)
+	dependencies, err := repo.ListDependencies(context.Background(), testTaskID)
+
+	assert.NoError(err)
+	assert.Equal(model.TaskDependencies{
+		BlockedBy: []model.Task{blocker},
+		Blocks:    []model.Task{},
+	}, dependencies)
+
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestAddDependency(t *testing.T) {
+	tests := map[string]struct {
+		blockerID   string
+		shouldError bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"valid": {
+			blockerID: testBlockerID,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				expectTaskRow(mock, model.Task{ID: testBlockerID, Name: "Task 2"})
+				expectBlockerIDs(mock, testBlockerID, testSubtaskID)
+				expectBlockerIDs(mock, testSubtaskID)
+				mock.ExpectExec("INSERT INTO task_dependencies").
+					WithArgs(testTaskID, testBlockerID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		"self": {
+			blockerID:   testTaskID,
+			shouldError: true,
+			sql:         func(mock sqlmock.Sqlmock) {},
+		},
+		"invalid_blocker_id": {
+			blockerID:   "invalid",
+			shouldError: true,
+			sql:         func(mock sqlmock.Sqlmock) {},
+		},
+		"blocker_not_found": {
+			blockerID:   testBlockerID,
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				mock.ExpectQuery("SELECT (.+) FROM tasks WHERE id = \\? AND deleted_at IS NULL").
+					WithArgs(testBlockerID).
+					WillReturnRows(taskRows(mock))
+			},
+		},
+		"cycle": {
+			blockerID:   testBlockerID,
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				expectTaskRow(mock, model.Task{ID: testBlockerID, Name: "Task 2"})
+				expectBlockerIDs(mock, testBlockerID, testSubtaskID)
+				expectBlockerIDs(mock, testSubtaskID, testTaskID)
+				expectBlockerIDs(mock, testTaskID)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, nil)
+			err := repo.AddDependency(context.Background(), testTaskID, test.blockerID)
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRemoveDependency(t *testing.T) {
+	tests := map[string]struct {
+		affected    int64
+		shouldError bool
+	}{
+		"valid": {
+			affected: 1,
+		},
+		"not_found": {
+			affected:    0,
+			shouldError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			expectTask(mock, true)
+			mock.ExpectExec("DELETE FROM task_dependencies WHERE task_id = \\? AND blocker_id = \\?").
+				WithArgs(testTaskID, testBlockerID).
+				WillReturnResult(sqlmock.NewResult(0, test.affected))
+
+			repo := NewTaskRepository(db, nil)
+			err := repo.RemoveDependency(context.Background(), testTaskID, testBlockerID)
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
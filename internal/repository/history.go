@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+// Lists the changes made to the task with the given ID, oldest first. The
+// history of tasks in the trash is kept until they are purged.
+func (r *TaskRepository) GetHistory(ctx context.Context, id string) ([]model.HistoryEntry, error) {
+	if err := model.ValidateID(id); err != nil {
+		return nil, err
+	}
+
+	var taskID string
+	if err := r.conn(ctx).QueryRowContext(ctx, "SELECT id FROM tasks WHERE id = ?", id).Scan(&taskID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewExternalError("Task not found.")
+		}
+
+		return nil, fmt.Errorf("Failed to get task by ID: %w", err)
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, "SELECT id, task_id, action, actor, changes, created_at FROM task_history WHERE task_id = ? ORDER BY created_at, id", id)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query task history: %w", err)
+	}
+
+	defer rows.Close()
+
+	entries := []model.HistoryEntry{}
+	for rows.Next() {
+		var entry model.HistoryEntry
+		var changes []byte
+
+		if err := rows.Scan(&entry.ID, &entry.TaskID, &entry.Action, &entry.Actor, &changes, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("Failed to scan task history: %w", err)
+		}
+
+		if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+			return nil, fmt.Errorf("Failed to decode task history: %w", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to scan task history: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Records a change made to a task by the given actor in its history.
+func (r *TaskRepository) recordHistory(ctx context.Context, taskID string, action model.HistoryAction, actor string, changes []model.FieldChange) error {
+	return r.insertHistory(ctx, []model.HistoryEntry{model.NewHistoryEntry(taskID, action, actor, changes)})
+}
+
+// Inserts the given history entries with a single statement.
+func (r *TaskRepository) insertHistory(ctx context.Context, entries []model.HistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	rows := make([]string, len(entries))
+	args := []interface{}{}
+
+	for i, entry := range entries {
+		encoded, err := json.Marshal(entry.Changes)
+		if err != nil {
+			return fmt.Errorf("Failed to encode task history: %w", err)
+		}
+
+		rows[i] = "(" + placeholders(6) + ")"
+		args = append(args, entry.ID, entry.TaskID, entry.Action, entry.Actor, encoded, entry.CreatedAt)
+	}
+
+	if _, err := r.conn(ctx).ExecContext(
+		ctx,
+		"INSERT INTO task_history (id, task_id, action, actor, changes, created_at) VALUES "+strings.Join(rows, ", "),
+		args...,
+	); err != nil {
+		return fmt.Errorf("Failed to record task history: %w", err)
+	}
+
+	return nil
+}
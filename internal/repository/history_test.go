@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+const testActor = "alice"
+
+func expectHistory(mock sqlmock.Sqlmock, taskID interface{}, action model.HistoryAction, changes interface{}) {
+	mock.ExpectExec("INSERT INTO task_history").
+		WithArgs(CUID{}, taskID, action, testActor, changes, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func TestGetHistory(t *testing.T) {
+	createdAt := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	changes := []model.FieldChange{
+		{Field: "name", From: json.RawMessage(`"Task 1"`), To: json.RawMessage(`"Task 2"`)},
+	}
+
+	tests := map[string]struct {
+		expected    []model.HistoryEntry
+		shouldError bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"existing": {
+			expected: []model.HistoryEntry{
+				{ID: "1", TaskID: testTaskID, Action: model.HistoryCreated, Actor: testActor, Changes: []model.FieldChange{}, CreatedAt: createdAt},
+				{ID: "2", TaskID: testTaskID, Action: model.HistoryUpdated, Actor: "", Changes: changes, CreatedAt: createdAt},
+			},
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT id FROM tasks WHERE id = \\?").
+					WithArgs(testTaskID).
+					WillReturnRows(mock.NewRows([]string{"id"}).AddRow(testTaskID))
+				mock.ExpectQuery("SELECT (.+) FROM task_history WHERE task_id = \\? ORDER BY created_at, id").
+					WithArgs(testTaskID).
+					WillReturnRows(mock.NewRows([]string{"id", "task_id", "action", "actor", "changes", "created_at"}).
+						AddRow("1", testTaskID, model.HistoryCreated, testActor, []byte(`[]`), createdAt).
+						AddRow("2", testTaskID, model.HistoryUpdated, "", []byte(`[{"field":"name","from":"Task 1","to":"Task 2"}]`), createdAt))
+			},
+		},
+		"task_not_found": {
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT id FROM tasks WHERE id = \\?").
+					WithArgs(testTaskID).
+					WillReturnRows(mock.NewRows([]string{"id"}))
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, nil)
+			history, err := repo.GetHistory(context.Background(), testTaskID)
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				assert.Equal(test.expected, history)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+// Moves the task with the given ID in the manual order, right after one task,
+// right before another, or between both. Only the moved task is rewritten.
+func (r *TaskRepository) ReorderTask(ctx context.Context, id string, afterID *string, beforeID *string) error {
+	if err := model.ValidateNeighbors(id, afterID, beforeID); err != nil {
+		return err
+	}
+
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	lower, upper := "", ""
+
+	if afterID != nil {
+		after, err := r.getNeighbor(ctx, *afterID)
+		if err != nil {
+			return err
+		}
+
+		lower = after.Position
+	}
+
+	if beforeID != nil {
+		before, err := r.getNeighbor(ctx, *beforeID)
+		if err != nil {
+			return err
+		}
+
+		upper = before.Position
+	}
+
+	var err error
+	if beforeID == nil {
+		upper, err = r.adjacentPosition(ctx, "SELECT position FROM tasks WHERE position > ? AND id <> ? ORDER BY position LIMIT 1", lower, id)
+	} else if afterID == nil {
+		lower, err = r.adjacentPosition(ctx, "SELECT position FROM tasks WHERE position < ? AND id <> ? ORDER BY position DESC LIMIT 1", upper, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	position, err := model.PositionBetween(lower, upper)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.conn(ctx).ExecContext(ctx, "UPDATE tasks SET position = ?, updated_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL", position, model.Now(), id); err != nil {
+		return fmt.Errorf("Failed to reorder task: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) getNeighbor(ctx context.Context, id string) (model.Task, error) {
+	task, err := r.GetByID(ctx, id)
+	if err != nil && errors.IsExternal(err) {
+		return model.Task{}, errors.NewExternalError("Neighbor task not found.")
+	}
+
+	return task, err
+}
+
+// Returns the position found by the given query, or an empty position when
+// there is none. Tasks in the trash keep their positions, so they are taken
+// into account to avoid sharing them.
+func (r *TaskRepository) adjacentPosition(ctx context.Context, query string, args ...interface{}) (string, error) {
+	var position string
+	err := r.conn(ctx).QueryRowContext(ctx, query, args...).Scan(&position)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("Failed to get adjacent position: %w", err)
+	}
+
+	return position, nil
+}
+
+// Returns the position after all tasks, where new tasks are placed.
+func (r *TaskRepository) nextPosition(ctx context.Context) (string, error) {
+	var last sql.NullString
+	if err := r.conn(ctx).QueryRowContext(ctx, "SELECT MAX(position) FROM tasks").Scan(&last); err != nil {
+		return "", fmt.Errorf("Failed to get last position: %w", err)
+	}
+
+	return model.PositionBetween(last.String, "")
+}
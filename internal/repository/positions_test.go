@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+const (
+	testAfterID  = "cl09rb83d000009l13y5n5af1"
+	testBeforeID = "cl09rb83d000009l13y5n5bf1"
+)
+
+func expectLastPosition(mock sqlmock.Sqlmock, last interface{}) {
+	mock.ExpectQuery("SELECT MAX\\(position\\) FROM tasks").
+		WillReturnRows(mock.NewRows([]string{"position"}).AddRow(last))
+}
+
+func expectAdjacentPosition(mock sqlmock.Sqlmock, operator string, position string, adjacent ...string) {
+	rows := mock.NewRows([]string{"position"})
+	for _, p := range adjacent {
+		rows.AddRow(p)
+	}
+
+	mock.ExpectQuery("SELECT position FROM tasks WHERE position "+operator+" \\? AND id <> \\?").
+		WithArgs(position, testTaskID).
+		WillReturnRows(rows)
+}
+
+func TestReorderTask(t *testing.T) {
+	afterID := testAfterID
+	beforeID := testBeforeID
+
+	after := model.Task{ID: testAfterID, Name: "Task 2", Position: "F"}
+	before := model.Task{ID: testBeforeID, Name: "Task 3", Position: "V"}
+
+	tests := map[string]struct {
+		afterID     *string
+		beforeID    *string
+		shouldError bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"between": {
+			afterID:  &afterID,
+			beforeID: &beforeID,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				expectTaskRow(mock, after)
+				expectTaskRow(mock, before)
+				mock.ExpectExec("UPDATE tasks SET position = \\?, updated_at = \\?, version = version \\+ 1 WHERE id = \\?").
+					WithArgs("N", sqlmock.AnyArg(), testTaskID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		"after_last": {
+			afterID: &afterID,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				expectTaskRow(mock, after)
+				expectAdjacentPosition(mock, ">", "F")
+				mock.ExpectExec("UPDATE tasks SET position = \\?, updated_at = \\?, version = version \\+ 1 WHERE id = \\?").
+					WithArgs("G", sqlmock.AnyArg(), testTaskID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		"before": {
+			beforeID: &beforeID,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				expectTaskRow(mock, before)
+				expectAdjacentPosition(mock, "<", "V", "U")
+				mock.ExpectExec("UPDATE tasks SET position = \\?, updated_at = \\?, version = version \\+ 1 WHERE id = \\?").
+					WithArgs("UV", sqlmock.AnyArg(), testTaskID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		"neighbors_out_of_order": {
+			afterID:     &beforeID,
+			beforeID:    &afterID,
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				expectTaskRow(mock, before)
+				expectTaskRow(mock, after)
+			},
+		},
+		"neighbor_not_found": {
+			afterID:     &afterID,
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				mock.ExpectQuery("SELECT (.+) FROM tasks WHERE id = \\? AND deleted_at IS NULL").
+					WithArgs(testAfterID).
+					WillReturnRows(taskRows(mock))
+			},
+		},
+		"no_neighbors": {
+			shouldError: true,
+			sql:         func(mock sqlmock.Sqlmock) {},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, nil)
+			err := repo.ReorderTask(context.Background(), testTaskID, test.afterID, test.beforeID)
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
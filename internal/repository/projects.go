@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+const projectColumns = "id, name, created_at, updated_at"
+
+func scanProject(row scanner) (model.Project, error) {
+	var project model.Project
+
+	if err := row.Scan(&project.ID, &project.Name, &project.CreatedAt, &project.UpdatedAt); err != nil {
+		return model.Project{}, err
+	}
+
+	return project, nil
+}
+
+// Lists all projects ordered by name.
+func (r *TaskRepository) ListProjects(ctx context.Context) ([]model.Project, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, "SELECT "+projectColumns+" FROM projects ORDER BY name, id")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query projects: %w", err)
+	}
+
+	defer rows.Close()
+
+	projects := []model.Project{}
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to scan project: %w", err)
+		}
+		projects = append(projects, project)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to scan project: %w", err)
+	}
+
+	return projects, nil
+}
+
+// Gets a project by ID and returns it.
+func (r *TaskRepository) GetProject(ctx context.Context, id string) (model.Project, error) {
+	if err := model.ValidateProjectID(id); err != nil {
+		return model.Project{}, err
+	}
+
+	project, err := scanProject(r.conn(ctx).QueryRowContext(ctx, "SELECT "+projectColumns+" FROM projects WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.Project{}, errors.NewExternalError("Project not found.")
+		}
+
+		return model.Project{}, fmt.Errorf("Failed to get project by ID: %w", err)
+	}
+
+	return project, nil
+}
+
+// Creates a new project from the given one and returns it. The ID and
+// timestamps are assigned by the repository.
+func (r *TaskRepository) CreateProject(ctx context.Context, project model.Project) (model.Project, error) {
+	project.Init()
+
+	if err := project.Validate(); err != nil {
+		return model.Project{}, err
+	}
+
+	if _, err := r.conn(ctx).ExecContext(
+		ctx,
+		"INSERT INTO projects (id, name, created_at, updated_at) VALUES (?, ?, ?, ?)",
+		project.ID, project.Name, project.CreatedAt, project.UpdatedAt,
+	); err != nil {
+		return model.Project{}, fmt.Errorf("Failed to create project: %w", err)
+	}
+
+	return project, nil
+}
+
+// Renames the given project.
+func (r *TaskRepository) UpdateProject(ctx context.Context, project model.Project) error {
+	if err := project.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := r.conn(ctx).ExecContext(ctx, "UPDATE projects SET name = ?, updated_at = ? WHERE id = ?", project.Name, model.Now(), project.ID); err != nil {
+		return fmt.Errorf("Failed to update project: %w", err)
+	}
+
+	return nil
+}
+
+// Deletes the project with the given ID. Its tasks are kept and no longer
+// belong to any project.
+func (r *TaskRepository) DeleteProject(ctx context.Context, id string) error {
+	if err := model.ValidateProjectID(id); err != nil {
+		return err
+	}
+
+	if _, err := r.conn(ctx).ExecContext(ctx, "UPDATE tasks SET project_id = NULL WHERE project_id = ?", id); err != nil {
+		return fmt.Errorf("Failed to remove tasks from project: %w", err)
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM projects WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("Failed to delete project: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to delete project: %w", err)
+	}
+
+	if affected == 0 {
+		return errors.NewExternalError("Project not found.")
+	}
+
+	return nil
+}
+
+// Moves the task with the given ID to the project with the given ID, or out
+// of its project when the project ID is nil, on behalf of the given actor.
+func (r *TaskRepository) MoveTask(ctx context.Context, id string, projectID *string, actor string) error {
+	if err := model.ValidateActor(actor); err != nil {
+		return err
+	}
+
+	if projectID != nil {
+		if _, err := r.GetProject(ctx, *projectID); err != nil {
+			return err
+		}
+	}
+
+	current, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.conn(ctx).ExecContext(ctx, "UPDATE tasks SET project_id = ?, updated_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL", projectID, model.Now(), id); err != nil {
+		return fmt.Errorf("Failed to move task: %w", err)
+	}
+
+	moved := current
+	moved.ProjectID = projectID
+
+	if changes := model.DiffTasks(current, moved); len(changes) > 0 {
+		return r.recordHistory(ctx, id, model.HistoryUpdated, actor, changes)
+	}
+
+	return nil
+}
@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+const testProjectID = "cl09rb83d000009l13y5n5pr1"
+
+var projectColumnNames = []string{"id", "name", "created_at", "updated_at"}
+
+func projectRows(mock sqlmock.Sqlmock, projects ...model.Project) *sqlmock.Rows {
+	rows := mock.NewRows(projectColumnNames)
+	for _, project := range projects {
+		rows.AddRow(project.ID, project.Name, project.CreatedAt, project.UpdatedAt)
+	}
+
+	return rows
+}
+
+func TestListProjects(t *testing.T) {
+	createdAt := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	projects := []model.Project{
+		{ID: "1", Name: "Home", CreatedAt: createdAt, UpdatedAt: createdAt},
+		{ID: "2", Name: "Work", CreatedAt: createdAt, UpdatedAt: createdAt},
+	}
+
+	assert, db, mock := beforeAll(t)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM projects ORDER BY name, id").
+		WillReturnRows(projectRows(mock, projects...))
+
+	repo := NewTaskRepository(db, nil)
+	actual, err := repo.ListProjects(context.Background())
+
+	assert.NoError(err)
+	assert.Equal(projects, actual)
+
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestGetProject(t *testing.T) {
+	tests := map[string]struct {
+		id          string
+		expected    model.Project
+		shouldError bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"invalid_id": {
+			id:          "1",
+			shouldError: true,
+			sql:         func(mock sqlmock.Sqlmock) {},
+		},
+		"existing": {
+			id:       testProjectID,
+			expected: model.Project{ID: testProjectID, Name: "Home"},
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM projects WHERE id = \\?").
+					WithArgs(testProjectID).
+					WillReturnRows(projectRows(mock, model.Project{ID: testProjectID, Name: "Home"}))
+			},
+		},
+		"non_existing": {
+			id:          testProjectID,
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM projects WHERE id = \\?").
+					WithArgs(testProjectID).
+					WillReturnRows(projectRows(mock))
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, nil)
+			project, err := repo.GetProject(context.Background(), test.id)
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				assert.Equal(test.expected, project)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCreateProject(t *testing.T) {
+	tests := map[string]struct {
+		name        string
+		shouldError bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"empty_name": {
+			name:        "",
+			shouldError: true,
+			sql:         func(mock sqlmock.Sqlmock) {},
+		},
+		"valid": {
+			name: "Home",
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("INSERT INTO projects").
+					WithArgs(CUID{}, "Home", sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, nil)
+			project, err := repo.CreateProject(context.Background(), model.Project{Name: test.name})
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				assert.Equal(test.name, project.Name)
+				assert.NoError(model.ValidateProjectID(project.ID))
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateProject(t *testing.T) {
+	assert, db, mock := beforeAll(t)
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE projects SET name = \\?, updated_at = \\? WHERE id = \\?").
+		WithArgs("Work", sqlmock.AnyArg(), testProjectID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewTaskRepository(db, nil)
+	err := repo.UpdateProject(context.Background(), model.Project{ID: testProjectID, Name: "Work"})
+
+	assert.NoError(err)
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestDeleteProject(t *testing.T) {
+	tests := map[string]struct {
+		shouldError bool
+		deleted     int64
+	}{
+		"existing": {
+			deleted: 1,
+		},
+		"non_existing": {
+			shouldError: true,
+			deleted:     0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			mock.ExpectExec("UPDATE tasks SET project_id = NULL WHERE project_id = \\?").
+				WithArgs(testProjectID).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+			mock.ExpectExec("DELETE FROM projects WHERE id = \\?").
+				WithArgs(testProjectID).
+				WillReturnResult(sqlmock.NewResult(0, test.deleted))
+
+			repo := NewTaskRepository(db, nil)
+			err := repo.DeleteProject(context.Background(), testProjectID)
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMoveTask(t *testing.T) {
+	projectID := testProjectID
+
+	tests := map[string]struct {
+		projectID   *string
+		shouldError bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"into_project": {
+			projectID: &projectID,
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM projects WHERE id = \\?").
+					WithArgs(testProjectID).
+					WillReturnRows(projectRows(mock, model.Project{ID: testProjectID, Name: "Home"}))
+				expectTask(mock, true)
+				mock.ExpectExec("UPDATE tasks SET project_id = \\?, updated_at = \\?, version = version \\+ 1 WHERE id = \\?").
+					WithArgs(testProjectID, sqlmock.AnyArg(), testTaskID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectHistory(mock, testTaskID, model.HistoryUpdated, []byte(`[{"field":"project_id","from":null,"to":"`+testProjectID+`"}]`))
+			},
+		},
+		"out_of_project": {
+			projectID: nil,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				mock.ExpectExec("UPDATE tasks SET project_id = \\?, updated_at = \\?, version = version \\+ 1 WHERE id = \\?").
+					WithArgs(nil, sqlmock.AnyArg(), testTaskID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		"project_not_found": {
+			projectID:   &projectID,
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM projects WHERE id = \\?").
+					WithArgs(testProjectID).
+					WillReturnRows(projectRows(mock))
+			},
+		},
+		"task_not_found": {
+			projectID:   nil,
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, false)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, nil)
+			err := repo.MoveTask(context.Background(), testTaskID, test.projectID, testActor)
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+// Creates the next occurrence of the given recurring task, completed at the
+// given time by the given actor, carrying over its tags.
+func (r *TaskRepository) createNextOccurrence(ctx context.Context, task model.Task, completedAt time.Time, actor string) error {
+	next, err := task.NextOccurrence(completedAt)
+	if err != nil || next == nil {
+		return err
+	}
+
+	created, err := r.Create(ctx, *next, actor)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.conn(ctx).ExecContext(ctx, "INSERT INTO task_tags (task_id, tag_id) SELECT ?, tag_id FROM task_tags WHERE task_id = ?", created.ID, task.ID); err != nil {
+		return fmt.Errorf("Failed to copy tags to next occurrence: %w", err)
+	}
+
+	return nil
+}
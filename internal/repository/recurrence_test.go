@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+func TestUpdateRecurring(t *testing.T) {
+	dueAt := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	nextDueAt := time.Date(2022, 3, 8, 12, 0, 0, 0, time.UTC)
+	task := model.Task{ID: testTaskID, Name: "Task 1", Completed: true, Status: model.StatusDone, Recurrence: "FREQ=WEEKLY", DueAt: &dueAt}
+
+	tests := map[string]struct {
+		sql func(mock sqlmock.Sqlmock)
+	}{
+		"creates_next_occurrence": {
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTaskRow(mock, model.Task{ID: testTaskID, Name: "Task 1", Status: model.StatusTodo, Recurrence: "FREQ=WEEKLY", DueAt: &dueAt})
+				expectOpenBlockers(mock, testTaskID, 0)
+				mock.ExpectExec("UPDATE tasks SET name = \\?").
+					WithArgs("Task 1", "", true, model.StatusDone, true, sqlmock.AnyArg(), model.PriorityNone, nil, false, "FREQ=WEEKLY", dueAt, nil, sqlmock.AnyArg(), testTaskID, 0).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectHistory(mock, testTaskID, model.HistoryUpdated, sqlmock.AnyArg())
+				expectLastPosition(mock, nil)
+				mock.ExpectExec("INSERT INTO tasks").
+					WithArgs(CUID{}, "Task 1", "", false, model.StatusTodo, "V", model.PriorityNone, nil, nil, false, "FREQ=WEEKLY", sqlmock.AnyArg(), sqlmock.AnyArg(), nil, nextDueAt, 1, dueAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectHistory(mock, CUID{}, model.HistoryCreated, sqlmock.AnyArg())
+				mock.ExpectExec("INSERT INTO task_tags \\(task_id, tag_id\\) SELECT \\?, tag_id FROM task_tags WHERE task_id = \\?").
+					WithArgs(CUID{}, testTaskID).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		"already_completed": {
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTaskRow(mock, task)
+				mock.ExpectExec("UPDATE tasks SET name = \\?").
+					WithArgs("Task 1", "", true, model.StatusDone, true, sqlmock.AnyArg(), model.PriorityNone, nil, false, "FREQ=WEEKLY", dueAt, nil, sqlmock.AnyArg(), testTaskID, 0).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, nil)
+			err := repo.Update(context.Background(), task, testActor)
+
+			assert.NoError(err)
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -1,70 +1,273 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
-	"github.com/lucsky/cuid"
+	"github.com/go-sql-driver/mysql"
+	"github.com/mtbuzato/go-challenge/internal/blob"
 	"github.com/mtbuzato/go-challenge/internal/errors"
 	"github.com/mtbuzato/go-challenge/internal/model"
 )
 
+const taskColumns = "id, name, description, completed, status, position, priority, project_id, parent_id, auto_complete, recurrence, created_at, updated_at, completed_at, due_at, deleted_at, version, recurrence_start"
+
+// MySQL error returned when there is no FULLTEXT index for a MATCH query.
+const errNoFullTextIndex = 1191
+
 type TaskRepository struct {
-	db *sql.DB
+	db       *sql.DB
+	blobs    blob.Store
+	fullText bool
 }
 
-func NewTaskRepository(db *sql.DB) *TaskRepository {
-	return &TaskRepository{db: db}
+// Creates a repository storing tasks in the given database and the contents
+// of their attachments in the given blob store.
+func NewTaskRepository(db *sql.DB, blobs blob.Store) *TaskRepository {
+	_, isMySQL := db.Driver().(*mysql.MySQLDriver)
+	return &TaskRepository{db: db, blobs: blobs, fullText: isMySQL}
 }
 
-// Lists all tasks.
-func (r *TaskRepository) ListAll() ([]model.Task, error) {
-	rows, err := r.db.Query("SELECT * FROM tasks")
-	if err != nil {
-		return nil, fmt.Errorf("Failed to query tasks: %w", err)
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTask(row scanner) (model.Task, error) {
+	var task model.Task
+	var projectID, parentID sql.NullString
+	var completedAt, dueAt, deletedAt, recurrenceStart sql.NullTime
+
+	if err := row.Scan(&task.ID, &task.Name, &task.Description, &task.Completed, &task.Status, &task.Position, &task.Priority, &projectID, &parentID, &task.AutoComplete, &task.Recurrence, &task.CreatedAt, &task.UpdatedAt, &completedAt, &dueAt, &deletedAt, &task.Version, &recurrenceStart); err != nil {
+		return model.Task{}, err
+	}
+
+	if projectID.Valid {
+		task.ProjectID = &projectID.String
+	}
+
+	if parentID.Valid {
+		task.ParentID = &parentID.String
+	}
+
+	if completedAt.Valid {
+		task.CompletedAt = &completedAt.Time
 	}
+
+	if dueAt.Valid {
+		task.DueAt = &dueAt.Time
+	}
+
+	if deletedAt.Valid {
+		task.DeletedAt = &deletedAt.Time
+	}
+
+	if recurrenceStart.Valid {
+		task.RecurrenceStart = &recurrenceStart.Time
+	}
+
+	return task, nil
+}
+
+func scanTasks(rows *sql.Rows) ([]model.Task, error) {
 	defer rows.Close()
 
 	tasks := []model.Task{}
 	for rows.Next() {
-		var task model.Task
-		if err := rows.Scan(&task.ID, &task.Name, &task.Completed); err != nil {
+		task, err := scanTask(rows)
+		if err != nil {
 			return nil, fmt.Errorf("Failed to scan task: %w", err)
 		}
 		tasks = append(tasks, task)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to scan task: %w", err)
+	}
+
 	return tasks, nil
 }
 
-// Lists all tasks with the matching completion status.
-func (r *TaskRepository) ListByCompletion(completed bool) ([]model.Task, error) {
-	rows, err := r.db.Query("SELECT * FROM tasks WHERE completed = ?", completed)
+// Lists all tasks that are not in the trash in their manual order.
+func (r *TaskRepository) ListAll(ctx context.Context) ([]model.Task, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, "SELECT "+taskColumns+" FROM tasks WHERE deleted_at IS NULL ORDER BY position, id")
 	if err != nil {
 		return nil, fmt.Errorf("Failed to query tasks: %w", err)
 	}
-	defer rows.Close()
 
-	tasks := []model.Task{}
-	for rows.Next() {
-		var task model.Task
-		if err := rows.Scan(&task.ID, &task.Name, &task.Completed); err != nil {
-			return nil, fmt.Errorf("Failed to scan task: %w", err)
+	return scanTasks(rows)
+}
+
+// Lists all tasks that are not in the trash with the matching completion status
+// in their manual order.
+func (r *TaskRepository) ListByCompletion(ctx context.Context, completed bool) ([]model.Task, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, "SELECT "+taskColumns+" FROM tasks WHERE completed = ? AND deleted_at IS NULL ORDER BY position, id", completed)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query tasks: %w", err)
+	}
+
+	return scanTasks(rows)
+}
+
+// Lists a page of tasks matching the given query.
+func (r *TaskRepository) Query(ctx context.Context, query model.TaskQuery) (model.TaskPage, error) {
+	if err := query.Validate(); err != nil {
+		return model.TaskPage{}, err
+	}
+
+	where := []string{"deleted_at IS NULL"}
+	args := []interface{}{}
+
+	if query.Name != "" {
+		where = append(where, "name LIKE ?")
+		args = append(args, query.NamePattern())
+	}
+
+	if query.Completed != nil {
+		where = append(where, "completed = ?")
+		args = append(args, *query.Completed)
+	}
+
+	if query.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, query.Status)
+	}
+
+	if query.ProjectID != "" {
+		where = append(where, "project_id = ?")
+		args = append(args, query.ProjectID)
+	}
+
+	if query.CreatedAfter != nil {
+		where = append(where, "created_at >= ?")
+		args = append(args, *query.CreatedAfter)
+	}
+
+	if query.CreatedBefore != nil {
+		where = append(where, "created_at < ?")
+		args = append(args, *query.CreatedBefore)
+	}
+
+	if query.UpdatedAfter != nil {
+		where = append(where, "updated_at >= ?")
+		args = append(args, *query.UpdatedAfter)
+	}
+
+	if query.UpdatedBefore != nil {
+		where = append(where, "updated_at < ?")
+		args = append(args, *query.UpdatedBefore)
+	}
+
+	now := model.Now()
+
+	if query.Overdue {
+		where = append(where, "due_at < ? AND completed = ?")
+		args = append(args, now, false)
+	}
+
+	if query.DueWithinDays > 0 {
+		where = append(where, "due_at >= ? AND due_at < ?")
+		args = append(args, now, now.AddDate(0, 0, query.DueWithinDays))
+	}
+
+	if tags, tagArgs := query.TagCondition(); tags != "" {
+		where = append(where, tags)
+		args = append(args, tagArgs...)
+	}
+
+	if blocked, blockedArgs := query.BlockedCondition(); blocked != "" {
+		where = append(where, blocked)
+		args = append(args, blockedArgs...)
+	}
+
+	if after, afterArgs := query.AfterCondition(); after != "" {
+		where = append(where, after)
+		args = append(args, afterArgs...)
+	}
+
+	limit := query.Page.PageLimit()
+	args = append(args, limit+1)
+
+	rows, err := r.conn(ctx).QueryContext(ctx, "SELECT "+taskColumns+" FROM tasks WHERE "+strings.Join(where, " AND ")+" ORDER BY "+query.OrderBy()+" LIMIT ?", args...)
+	if err != nil {
+		return model.TaskPage{}, fmt.Errorf("Failed to query tasks: %w", err)
+	}
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return model.TaskPage{}, err
+	}
+
+	return model.NewTaskPage(tasks, query), nil
+}
+
+// Searches tasks that are not in the trash by the words in their names and
+// returns them ranked by relevance. On MySQL this requires a FULLTEXT index
+// on the task names, otherwise a slower pattern search is used.
+func (r *TaskRepository) Search(ctx context.Context, query string, limit int) ([]model.Task, error) {
+	if err := model.ValidateSearch(query, limit); err != nil {
+		return nil, err
+	}
+
+	limit = model.SearchLimit(limit)
+
+	if r.fullText {
+		rows, err := r.conn(ctx).QueryContext(ctx, "SELECT "+taskColumns+" FROM tasks WHERE deleted_at IS NULL AND MATCH(name) AGAINST(? IN NATURAL LANGUAGE MODE) ORDER BY MATCH(name) AGAINST(? IN NATURAL LANGUAGE MODE) DESC, id LIMIT ?", query, query, limit)
+		if err == nil {
+			return scanTasks(rows)
 		}
-		tasks = append(tasks, task)
+
+		if mysqlErr, ok := err.(*mysql.MySQLError); !ok || mysqlErr.Number != errNoFullTextIndex {
+			return nil, fmt.Errorf("Failed to search tasks: %w", err)
+		}
+	}
+
+	terms := model.SearchTerms(query)
+	where := make([]string, len(terms))
+	args := make([]interface{}, len(terms))
+	for i, term := range terms {
+		where[i] = "LOWER(name) LIKE ?"
+		args[i] = model.LikePattern(term)
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, "SELECT "+taskColumns+" FROM tasks WHERE deleted_at IS NULL AND ("+strings.Join(where, " OR ")+") ORDER BY id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to search tasks: %w", err)
+	}
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	model.RankByRelevance(tasks, terms)
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
 	}
 
 	return tasks, nil
 }
 
-// Gets a task by ID and returns it.
-func (r *TaskRepository) GetByID(id string) (model.Task, error) {
+// Lists all tasks in the trash.
+func (r *TaskRepository) ListTrash(ctx context.Context) ([]model.Task, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, "SELECT "+taskColumns+" FROM tasks WHERE deleted_at IS NOT NULL")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query tasks: %w", err)
+	}
+
+	return scanTasks(rows)
+}
+
+// Gets a task by ID and returns it. Tasks in the trash are not returned.
+func (r *TaskRepository) GetByID(ctx context.Context, id string) (model.Task, error) {
 	if err := model.ValidateID(id); err != nil {
 		return model.Task{}, err
 	}
 
-	var task model.Task
-	if err := r.db.QueryRow("SELECT * FROM tasks WHERE id = ?", id).Scan(&task.ID, &task.Name, &task.Completed); err != nil {
+	task, err := scanTask(r.conn(ctx).QueryRowContext(ctx, "SELECT "+taskColumns+" FROM tasks WHERE id = ? AND deleted_at IS NULL", id))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return model.Task{}, errors.NewExternalError("Task not found.")
 		}
@@ -75,30 +278,327 @@ func (r *TaskRepository) GetByID(id string) (model.Task, error) {
 	return task, nil
 }
 
-// Creates a new task with the given name and returns it.
-func (r *TaskRepository) Create(name string) (model.Task, error) {
-	if err := model.ValidateName(name); err != nil {
+// Creates a new task from the given one on behalf of the given actor and
+// returns it. The ID and timestamps are assigned by the repository, and the
+// task is placed after all others.
+func (r *TaskRepository) Create(ctx context.Context, task model.Task, actor string) (model.Task, error) {
+	if err := model.ValidateActor(actor); err != nil {
+		return model.Task{}, err
+	}
+
+	task, err := r.prepareTask(ctx, task)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	tasks := []model.Task{task}
+	if err := r.createTasks(ctx, tasks, actor); err != nil {
+		return model.Task{}, err
+	}
+
+	return tasks[0], nil
+}
+
+// Prepares a task to be created, checking that it is valid and that its
+// project and parent exist.
+func (r *TaskRepository) prepareTask(ctx context.Context, task model.Task) (model.Task, error) {
+	task.Init()
+
+	if err := task.Validate(); err != nil {
 		return model.Task{}, err
 	}
 
-	id := cuid.New()
+	if task.ProjectID != nil {
+		if _, err := r.GetProject(ctx, *task.ProjectID); err != nil {
+			return model.Task{}, err
+		}
+	}
+
+	if task.ParentID != nil {
+		if err := r.validateParent(ctx, task.ID, *task.ParentID); err != nil {
+			return model.Task{}, err
+		}
+	}
+
+	return task, nil
+}
+
+// Inserts the given prepared tasks with a single statement, placing them after
+// all others in order, and records their creation in their history.
+func (r *TaskRepository) createTasks(ctx context.Context, tasks []model.Task, actor string) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	position, err := r.nextPosition(ctx)
+	if err != nil {
+		return err
+	}
+
+	rows := make([]string, len(tasks))
+	args := []interface{}{}
+	entries := make([]model.HistoryEntry, len(tasks))
 
-	if _, err := r.db.Exec("INSERT INTO tasks (id, name, completed) VALUES (?, ?, ?)", id, name, false); err != nil {
-		return model.Task{}, fmt.Errorf("Failed to create task: %w", err)
+	for i := range tasks {
+		if i > 0 {
+			if position, err = model.PositionBetween(position, ""); err != nil {
+				return err
+			}
+		}
+
+		task := &tasks[i]
+		task.Position = position
+
+		rows[i] = "(" + placeholders(17) + ")"
+		args = append(args, task.ID, task.Name, task.Description, task.Completed, task.Status, task.Position, task.Priority, task.ProjectID, task.ParentID, task.AutoComplete, task.Recurrence, task.CreatedAt, task.UpdatedAt, task.CompletedAt, task.DueAt, task.Version, task.RecurrenceStart)
+		entries[i] = model.NewHistoryEntry(task.ID, model.HistoryCreated, actor, model.DiffTasks(model.Task{}, *task))
+	}
+
+	if _, err := r.conn(ctx).ExecContext(
+		ctx,
+		"INSERT INTO tasks (id, name, description, completed, status, position, priority, project_id, parent_id, auto_complete, recurrence, created_at, updated_at, completed_at, due_at, version, recurrence_start) VALUES "+strings.Join(rows, ", "),
+		args...,
+	); err != nil {
+		return fmt.Errorf("Failed to create task: %w", err)
 	}
 
-	return model.Task{ID: id, Name: name, Completed: false}, nil
+	return r.insertHistory(ctx, entries)
+}
+
+// Returns a comma separated list of the given number of placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
 }
 
-// Updates the given task.
-func (r *TaskRepository) Update(task model.Task) error {
+// Updates the given task on behalf of the given actor, recording the changed
+// fields in its history. When the task has a version it must match the current
+// one, and either way the update fails with a conflict if the task is changed
+// by someone else in the meantime. The completion time is set when the task is
+// first completed and cleared when it is reopened. Status changes must be
+// allowed by the task workflow, and tasks blocked by open tasks cannot be
+// completed. Completing a recurring task creates its next occurrence, and
+// completing a task may complete its parents too. Tasks in the trash are left
+// untouched.
+func (r *TaskRepository) Update(ctx context.Context, task model.Task, actor string) error {
 	if err := task.Validate(); err != nil {
 		return err
 	}
 
-	if _, err := r.db.Exec("UPDATE tasks SET name = ?, completed = ? WHERE id = ?", task.Name, task.Completed, task.ID); err != nil {
+	if err := model.ValidateActor(actor); err != nil {
+		return err
+	}
+
+	current, err := r.GetByID(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+
+	if task.Version == 0 {
+		task.Version = current.Version
+	}
+
+	if task.Version != current.Version {
+		return errors.NewConflictError("Task has been changed since it was read.")
+	}
+
+	// The project and position are changed by moving the task instead.
+	task.ProjectID = current.ProjectID
+	task.Position = current.Position
+
+	// A recurring task keeps the start of its series until its rule changes.
+	task.RecurrenceStart = nil
+	if task.Recurrence == current.Recurrence {
+		task.RecurrenceStart = current.RecurrenceStart
+	}
+
+	task.ResolveStatus(current)
+
+	if err := model.ValidateTransition(current.Status, task.Status); err != nil {
+		return err
+	}
+
+	if task.ParentID != nil {
+		if err := r.validateParent(ctx, task.ID, *task.ParentID); err != nil {
+			return err
+		}
+	}
+
+	// Only completing a task requires its blockers to be done, so that a
+	// completed task can still be edited after one of them is reopened.
+	if task.Completed && !current.Completed {
+		if err := r.validateUnblocked(ctx, task.ID); err != nil {
+			return err
+		}
+	}
+
+	now := model.Now()
+
+	res, err := r.conn(ctx).ExecContext(
+		ctx,
+		"UPDATE tasks SET name = ?, description = ?, completed = ?, status = ?, completed_at = CASE WHEN ? THEN COALESCE(completed_at, ?) ELSE NULL END, priority = ?, parent_id = ?, auto_complete = ?, recurrence = ?, due_at = ?, recurrence_start = ?, updated_at = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL",
+		task.Name, task.Description, task.Completed, task.Status, task.Completed, now, task.Priority, task.ParentID, task.AutoComplete, task.Recurrence, task.DueAt, task.RecurrenceStart, now, task.ID, task.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("Failed to update task: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
 		return fmt.Errorf("Failed to update task: %w", err)
 	}
 
+	if affected == 0 {
+		return errors.NewConflictError("Task has been changed since it was read.")
+	}
+
+	if changes := model.DiffTasks(current, task); len(changes) > 0 {
+		if err := r.recordHistory(ctx, task.ID, model.HistoryUpdated, actor, changes); err != nil {
+			return err
+		}
+	}
+
+	if task.Completed && !current.Completed && task.Recurrence != "" {
+		if err := r.createNextOccurrence(ctx, task, now, actor); err != nil {
+			return err
+		}
+	}
+
+	if task.Completed && task.ParentID != nil {
+		return r.completeParents(ctx, *task.ParentID, actor)
+	}
+
 	return nil
 }
+
+// Partially updates the task with the given ID on behalf of the given actor,
+// changing only the fields listed in the patch. The rest of the task is kept
+// and updated as a whole, so the same rules and version checks apply.
+func (r *TaskRepository) Patch(ctx context.Context, id string, patch model.TaskPatch, actor string) error {
+	if err := patch.Validate(); err != nil {
+		return err
+	}
+
+	current, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return r.Update(ctx, patch.Apply(current), actor)
+}
+
+// Moves the task with the given ID to the trash on behalf of the given actor.
+func (r *TaskRepository) Delete(ctx context.Context, id string, actor string) error {
+	if err := model.ValidateID(id); err != nil {
+		return err
+	}
+
+	if err := model.ValidateActor(actor); err != nil {
+		return err
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, "UPDATE tasks SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL", model.Now(), id)
+	if err != nil {
+		return fmt.Errorf("Failed to delete task: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to delete task: %w", err)
+	}
+
+	if affected == 0 {
+		return errors.NewExternalError("Task not found.")
+	}
+
+	return r.recordHistory(ctx, id, model.HistoryDeleted, actor, nil)
+}
+
+// Restores the task with the given ID from the trash on behalf of the given
+// actor.
+func (r *TaskRepository) Restore(ctx context.Context, id string, actor string) error {
+	if err := model.ValidateID(id); err != nil {
+		return err
+	}
+
+	if err := model.ValidateActor(actor); err != nil {
+		return err
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, "UPDATE tasks SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		return fmt.Errorf("Failed to restore task: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to restore task: %w", err)
+	}
+
+	if affected == 0 {
+		return errors.NewExternalError("Task not found in trash.")
+	}
+
+	return r.recordHistory(ctx, id, model.HistoryRestored, actor, nil)
+}
+
+// Permanently deletes all tasks moved to the trash before the given time and
+// returns how many were removed. The content of their attachments is only
+// deleted once the transaction removing them is committed.
+func (r *TaskRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	var affected int64
+
+	err := r.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM task_tags WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?)", before.UTC()); err != nil {
+			return fmt.Errorf("Failed to purge task tags: %w", err)
+		}
+
+		attachments, err := r.purgeAttachments(ctx, before)
+		if err != nil {
+			return err
+		}
+
+		if err := r.afterCommit(ctx, func() error {
+			for _, id := range attachments {
+				if err := r.blobs.Delete(id); err != nil {
+					return fmt.Errorf("Failed to purge attachment content: %w", err)
+				}
+			}
+
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		if _, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM task_history WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?)", before.UTC()); err != nil {
+			return fmt.Errorf("Failed to purge task history: %w", err)
+		}
+
+		if _, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM comments WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?)", before.UTC()); err != nil {
+			return fmt.Errorf("Failed to purge task comments: %w", err)
+		}
+
+		if _, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM task_dependencies WHERE task_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?) OR blocker_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?)", before.UTC(), before.UTC()); err != nil {
+			return fmt.Errorf("Failed to purge task dependencies: %w", err)
+		}
+
+		if _, err := r.conn(ctx).ExecContext(ctx, "UPDATE tasks SET parent_id = NULL WHERE parent_id IN (SELECT id FROM (SELECT id FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?) AS purged)", before.UTC()); err != nil {
+			return fmt.Errorf("Failed to detach subtasks of purged tasks: %w", err)
+		}
+
+		res, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at < ?", before.UTC())
+		if err != nil {
+			return fmt.Errorf("Failed to purge tasks: %w", err)
+		}
+
+		if affected, err = res.RowsAffected(); err != nil {
+			return fmt.Errorf("Failed to purge tasks: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return affected, nil
+}
@@ -1,11 +1,14 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	"github.com/lucsky/cuid"
 	"github.com/mtbuzato/go-challenge/internal/model"
 	"github.com/stretchr/testify/assert"
@@ -18,6 +21,33 @@ func (c CUID) Match(v driver.Value) bool {
 	return err == nil
 }
 
+var taskColumnNames = []string{"id", "name", "description", "completed", "status", "position", "priority", "project_id", "parent_id", "auto_complete", "recurrence", "created_at", "updated_at", "completed_at", "due_at", "deleted_at", "version", "recurrence_start"}
+
+func nullString(s *string) driver.Value {
+	if s == nil {
+		return nil
+	}
+
+	return *s
+}
+
+func nullTime(t *time.Time) driver.Value {
+	if t == nil {
+		return nil
+	}
+
+	return *t
+}
+
+func taskRows(mock sqlmock.Sqlmock, tasks ...model.Task) *sqlmock.Rows {
+	rows := mock.NewRows(taskColumnNames)
+	for _, task := range tasks {
+		rows.AddRow(task.ID, task.Name, task.Description, task.Completed, task.Status, task.Position, task.Priority, nullString(task.ProjectID), nullString(task.ParentID), task.AutoComplete, task.Recurrence, task.CreatedAt, task.UpdatedAt, nullTime(task.CompletedAt), nullTime(task.DueAt), nullTime(task.DeletedAt), task.Version, nullTime(task.RecurrenceStart))
+	}
+
+	return rows
+}
+
 func beforeAll(t *testing.T) (*assert.Assertions, *sql.DB, sqlmock.Sqlmock) {
 	assert := assert.New(t)
 
@@ -48,8 +78,9 @@ func TestListAll(t *testing.T) {
 			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedQuery {
 				return mock.ExpectQuery("SELECT (.+) FROM tasks").
 					WillReturnRows(
-						mock.NewRows([]string{"id", "name", "completed"}).
-							AddRow("1", "Task 1", false),
+						taskRows(mock,
+							model.Task{ID: "1", Name: "Task 1", Completed: false},
+						),
 					)
 			},
 		},
@@ -60,12 +91,13 @@ func TestListAll(t *testing.T) {
 				{ID: "3", Name: "Task 3", Completed: false},
 			},
 			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedQuery {
-				return mock.ExpectQuery("SELECT (.+) FROM tasks").
+				return mock.ExpectQuery("SELECT (.+) FROM tasks WHERE deleted_at IS NULL ORDER BY position, id").
 					WillReturnRows(
-						mock.NewRows([]string{"id", "name", "completed"}).
-							AddRow("1", "Task 1", false).
-							AddRow("2", "Task 2", false).
-							AddRow("3", "Task 3", false),
+						taskRows(mock,
+							model.Task{ID: "1", Name: "Task 1", Completed: false},
+							model.Task{ID: "2", Name: "Task 2", Completed: false},
+							model.Task{ID: "3", Name: "Task 3", Completed: false},
+						),
 					)
 			},
 		},
@@ -78,8 +110,8 @@ func TestListAll(t *testing.T) {
 
 			test.query(mock)
 
-			repo := NewTaskRepository(db)
-			tasks, err := repo.ListAll()
+			repo := NewTaskRepository(db, nil)
+			tasks, err := repo.ListAll(context.Background())
 
 			assert.NoError(err)
 			assert.Equal(test.expected, tasks)
@@ -89,6 +121,23 @@ func TestListAll(t *testing.T) {
 	}
 }
 
+func TestListAllCanceled(t *testing.T) {
+	assert, db, mock := beforeAll(t)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE deleted_at IS NULL ORDER BY position, id").
+		WillDelayFor(time.Second).
+		WillReturnRows(taskRows(mock))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	repo := NewTaskRepository(db, nil)
+	_, err := repo.ListAll(ctx)
+
+	assert.EqualError(err, "Failed to query tasks: canceling query due to user request")
+}
+
 func TestListByCompletion(t *testing.T) {
 	tests := map[string]struct {
 		expected  []model.Task
@@ -112,8 +161,9 @@ func TestListByCompletion(t *testing.T) {
 				return mock.ExpectQuery("SELECT (.+) FROM tasks WHERE completed").
 					WithArgs(false).
 					WillReturnRows(
-						mock.NewRows([]string{"id", "name", "completed"}).
-							AddRow("1", "Task 1", false),
+						taskRows(mock,
+							model.Task{ID: "1", Name: "Task 1", Completed: false},
+						),
 					)
 			},
 			completed: false,
@@ -128,10 +178,11 @@ func TestListByCompletion(t *testing.T) {
 				return mock.ExpectQuery("SELECT (.+) FROM tasks WHERE completed").
 					WithArgs(true).
 					WillReturnRows(
-						mock.NewRows([]string{"id", "name", "completed"}).
-							AddRow("1", "Task 1", true).
-							AddRow("2", "Task 2", true).
-							AddRow("3", "Task 3", true),
+						taskRows(mock,
+							model.Task{ID: "1", Name: "Task 1", Completed: true},
+							model.Task{ID: "2", Name: "Task 2", Completed: true},
+							model.Task{ID: "3", Name: "Task 3", Completed: true},
+						),
 					)
 			},
 			completed: true,
@@ -145,8 +196,8 @@ func TestListByCompletion(t *testing.T) {
 
 			test.query(mock)
 
-			repo := NewTaskRepository(db)
-			tasks, err := repo.ListByCompletion(test.completed)
+			repo := NewTaskRepository(db, nil)
+			tasks, err := repo.ListByCompletion(context.Background(), test.completed)
 
 			assert.NoError(err)
 			assert.Equal(test.expected, tasks)
@@ -156,7 +207,256 @@ func TestListByCompletion(t *testing.T) {
 	}
 }
 
+func TestQuery(t *testing.T) {
+	completed := true
+	unblocked := false
+	createdAfter := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	last := model.Task{ID: "cl09rb83d000009l13y5n5ur2", Name: "Task 2", Completed: true}
+
+	tests := map[string]struct {
+		query    model.TaskQuery
+		expected model.TaskPage
+		sql      func(mock sqlmock.Sqlmock) *sqlmock.ExpectedQuery
+	}{
+		"invalid_sort": {
+			query:    model.TaskQuery{Sort: "invalid"},
+			expected: model.TaskPage{},
+			sql: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedQuery {
+				return nil
+			},
+		},
+		"first_page": {
+			query: model.TaskQuery{Page: model.PageRequest{Limit: 2}},
+			expected: model.TaskPage{
+				Tasks: []model.Task{
+					{ID: "cl09rb83d000009l13y5n5ur1", Name: "Task 1", Completed: false},
+					{ID: "cl09rb83d000009l13y5n5ur2", Name: "Task 2", Completed: false},
+				},
+				NextCursor: model.EncodeCursor(model.Task{ID: "cl09rb83d000009l13y5n5ur2"}, "id", false),
+			},
+			sql: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedQuery {
+				return mock.ExpectQuery("SELECT (.+) FROM tasks WHERE deleted_at IS NULL ORDER BY id ASC LIMIT").
+					WithArgs(3).
+					WillReturnRows(
+						taskRows(mock,
+							model.Task{ID: "cl09rb83d000009l13y5n5ur1", Name: "Task 1", Completed: false},
+							model.Task{ID: "cl09rb83d000009l13y5n5ur2", Name: "Task 2", Completed: false},
+							model.Task{ID: "cl09rb83d000009l13y5n5ur3", Name: "Task 3", Completed: false},
+						),
+					)
+			},
+		},
+		"sorted_by_priority": {
+			query: model.TaskQuery{Sort: "priority"},
+			expected: model.TaskPage{
+				Tasks: []model.Task{
+					{ID: "cl09rb83d000009l13y5n5ur2", Name: "Task 2", Priority: model.PriorityUrgent},
+					{ID: "cl09rb83d000009l13y5n5ur1", Name: "Task 1", Priority: model.PriorityLow},
+				},
+			},
+			sql: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedQuery {
+				return mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE deleted_at IS NULL ORDER BY priority DESC, COALESCE\(due_at, (.+)\) ASC, id ASC LIMIT`).
+					WithArgs(model.DefaultPageLimit + 1).
+					WillReturnRows(
+						taskRows(mock,
+							model.Task{ID: "cl09rb83d000009l13y5n5ur2", Name: "Task 2", Priority: model.PriorityUrgent},
+							model.Task{ID: "cl09rb83d000009l13y5n5ur1", Name: "Task 1", Priority: model.PriorityLow},
+						),
+					)
+			},
+		},
+		"tagged_with_all": {
+			query: model.TaskQuery{Tags: []string{"backend", "urgent"}, MatchAllTags: true},
+			expected: model.TaskPage{
+				Tasks: []model.Task{
+					{ID: "cl09rb83d000009l13y5n5ur1", Name: "Task 1"},
+				},
+			},
+			sql: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedQuery {
+				return mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE deleted_at IS NULL AND id IN \(SELECT task_tags.task_id FROM task_tags JOIN tags ON (.+) WHERE tags.name IN \(\?, \?\) GROUP BY task_tags.task_id HAVING COUNT\(\*\) = \?\) ORDER BY id ASC LIMIT`).
+					WithArgs("backend", "urgent", 2, model.DefaultPageLimit+1).
+					WillReturnRows(
+						taskRows(mock,
+							model.Task{ID: "cl09rb83d000009l13y5n5ur1", Name: "Task 1"},
+						),
+					)
+			},
+		},
+		"in_review": {
+			query: model.TaskQuery{Status: model.StatusReview},
+			expected: model.TaskPage{
+				Tasks: []model.Task{},
+			},
+			sql: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedQuery {
+				return mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE deleted_at IS NULL AND status = \? ORDER BY id ASC LIMIT`).
+					WithArgs(model.StatusReview, model.DefaultPageLimit+1).
+					WillReturnRows(mock.NewRows(nil))
+			},
+		},
+		"unblocked": {
+			query: model.TaskQuery{Blocked: &unblocked},
+			expected: model.TaskPage{
+				Tasks: []model.Task{},
+			},
+			sql: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedQuery {
+				return mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE deleted_at IS NULL AND id NOT IN \(SELECT task_dependencies.task_id FROM task_dependencies JOIN tasks AS blockers ON (.+) WHERE blockers.completed = \? AND blockers.deleted_at IS NULL\) ORDER BY id ASC LIMIT`).
+					WithArgs(false, model.DefaultPageLimit+1).
+					WillReturnRows(mock.NewRows(nil))
+			},
+		},
+		"overdue_due_within_week": {
+			query: model.TaskQuery{Overdue: true, DueWithinDays: 7},
+			expected: model.TaskPage{
+				Tasks: []model.Task{},
+			},
+			sql: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedQuery {
+				return mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE deleted_at IS NULL AND due_at < \? AND completed = \? AND due_at >= \? AND due_at < \? ORDER BY id ASC LIMIT`).
+					WithArgs(sqlmock.AnyArg(), false, sqlmock.AnyArg(), sqlmock.AnyArg(), model.DefaultPageLimit+1).
+					WillReturnRows(mock.NewRows(nil))
+			},
+		},
+		"filtered_last_page_sorted_by_name": {
+			query: model.TaskQuery{
+				Name:         "Task",
+				Completed:    &completed,
+				CreatedAfter: &createdAfter,
+				Sort:         "name",
+				Descending:   true,
+				Page:         model.PageRequest{Limit: 2, Cursor: model.EncodeCursor(last, "name", true)},
+			},
+			expected: model.TaskPage{
+				Tasks: []model.Task{
+					{ID: "cl09rb83d000009l13y5n5ur1", Name: "Task 1", Completed: true},
+				},
+			},
+			sql: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedQuery {
+				return mock.ExpectQuery(`SELECT (.+) FROM tasks WHERE deleted_at IS NULL AND name LIKE \? AND completed = \? AND created_at >= \? AND \(name < \? OR \(name = \? AND id < \?\)\) ORDER BY name DESC, id DESC LIMIT`).
+					WithArgs("%Task%", true, createdAfter, "Task 2", "Task 2", "cl09rb83d000009l13y5n5ur2", 3).
+					WillReturnRows(
+						taskRows(mock,
+							model.Task{ID: "cl09rb83d000009l13y5n5ur1", Name: "Task 1", Completed: true},
+						),
+					)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, nil)
+			page, err := repo.Query(context.Background(), test.query)
+
+			if test.expected.Tasks == nil {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				assert.Equal(test.expected, page)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	tests := map[string]struct {
+		query    string
+		fullText bool
+		expected []model.Task
+		sql      func(mock sqlmock.Sqlmock)
+	}{
+		"invalid_query": {
+			query:    "",
+			expected: nil,
+			sql:      func(mock sqlmock.Sqlmock) {},
+		},
+		"full_text": {
+			query:    "milk",
+			fullText: true,
+			expected: []model.Task{
+				{ID: "1", Name: "Buy milk", Completed: false},
+			},
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM tasks WHERE deleted_at IS NULL AND MATCH\\(name\\) AGAINST").
+					WithArgs("milk", "milk", model.DefaultSearchLimit).
+					WillReturnRows(
+						taskRows(mock,
+							model.Task{ID: "1", Name: "Buy milk", Completed: false},
+						),
+					)
+			},
+		},
+		"full_text_without_index": {
+			query:    "milk",
+			fullText: true,
+			expected: []model.Task{
+				{ID: "1", Name: "Buy milk", Completed: false},
+			},
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM tasks WHERE deleted_at IS NULL AND MATCH").
+					WillReturnError(&mysql.MySQLError{Number: 1191})
+				mock.ExpectQuery("SELECT (.+) FROM tasks WHERE deleted_at IS NULL AND \\(LOWER\\(name\\) LIKE").
+					WithArgs("%milk%").
+					WillReturnRows(
+						taskRows(mock,
+							model.Task{ID: "1", Name: "Buy milk", Completed: false},
+						),
+					)
+			},
+		},
+		"pattern_ranked": {
+			query: "buy milk",
+			expected: []model.Task{
+				{ID: "2", Name: "Buy milk", Completed: false},
+				{ID: "1", Name: "Buy bread", Completed: false},
+				{ID: "3", Name: "Drink milk", Completed: false},
+			},
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM tasks WHERE deleted_at IS NULL AND \\(LOWER\\(name\\) LIKE (.+) OR LOWER\\(name\\) LIKE (.+)\\)").
+					WithArgs("%buy%", "%milk%").
+					WillReturnRows(
+						taskRows(mock,
+							model.Task{ID: "1", Name: "Buy bread", Completed: false},
+							model.Task{ID: "2", Name: "Buy milk", Completed: false},
+							model.Task{ID: "3", Name: "Drink milk", Completed: false},
+						),
+					)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, nil)
+			repo.fullText = test.fullText
+			tasks, err := repo.Search(context.Background(), test.query, 0)
+
+			if test.expected == nil {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				assert.Equal(test.expected, tasks)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestGetByID(t *testing.T) {
+	createdAt := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	completedAt := createdAt.Add(time.Hour)
+
 	tests := map[string]struct {
 		id       string
 		expected model.Task
@@ -178,8 +478,28 @@ func TestGetByID(t *testing.T) {
 				return mock.ExpectQuery("SELECT (.+) FROM tasks WHERE id").
 					WithArgs("cl09rb83d000009l13y5n5ur8").
 					WillReturnRows(
-						mock.NewRows([]string{"id", "name", "completed"}).
-							AddRow("cl09rb83d000009l13y5n5ur8", "Task 1", false),
+						taskRows(mock,
+							model.Task{ID: "cl09rb83d000009l13y5n5ur8", Name: "Task 1", Completed: false},
+						),
+					)
+			},
+		},
+		"completed": {
+			id: "cl09rb83d000009l13y5n5ur8",
+			expected: model.Task{
+				ID: "cl09rb83d000009l13y5n5ur8", Name: "Task 1", Completed: true,
+				CreatedAt: createdAt, UpdatedAt: completedAt, CompletedAt: &completedAt,
+			},
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedQuery {
+				return mock.ExpectQuery("SELECT (.+) FROM tasks WHERE id").
+					WithArgs("cl09rb83d000009l13y5n5ur8").
+					WillReturnRows(
+						taskRows(mock,
+							model.Task{
+								ID: "cl09rb83d000009l13y5n5ur8", Name: "Task 1", Completed: true,
+								CreatedAt: createdAt, UpdatedAt: completedAt, CompletedAt: &completedAt,
+							},
+						),
 					)
 			},
 		},
@@ -203,8 +523,8 @@ func TestGetByID(t *testing.T) {
 
 			test.query(mock)
 
-			repo := NewTaskRepository(db)
-			task, err := repo.GetByID(test.id)
+			repo := NewTaskRepository(db, nil)
+			task, err := repo.GetByID(context.Background(), test.id)
 
 			if test.expected.ID == "" {
 				assert.Error(err)
@@ -220,24 +540,78 @@ func TestGetByID(t *testing.T) {
 }
 
 func TestCreate(t *testing.T) {
+	dueAt := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+	projectID := testProjectID
+
 	tests := map[string]struct {
-		name        string
+		task        model.Task
 		shouldError bool
 		query       func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec
 	}{
 		"empty_name": {
-			name:        "",
+			task:        model.Task{Name: ""},
+			shouldError: true,
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				return nil
+			},
+		},
+		"invalid_due_date": {
+			task:        model.Task{Name: "Task 1", DueAt: &time.Time{}},
 			shouldError: true,
 			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
 				return nil
 			},
 		},
 		"valid": {
-			name:        "Task 1",
+			task:        model.Task{Name: "Task 1"},
+			shouldError: false,
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				expectLastPosition(mock, nil)
+				return mock.ExpectExec("INSERT INTO tasks").
+					WithArgs(CUID{}, "Task 1", "", false, model.StatusTodo, "V", model.PriorityNone, nil, nil, false, "", sqlmock.AnyArg(), sqlmock.AnyArg(), nil, nil, 1, nil).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"valid_with_due_date": {
+			task:        model.Task{Name: "Task 1", DueAt: &dueAt},
+			shouldError: false,
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				expectLastPosition(mock, nil)
+				return mock.ExpectExec("INSERT INTO tasks").
+					WithArgs(CUID{}, "Task 1", "", false, model.StatusTodo, "V", model.PriorityNone, nil, nil, false, "", sqlmock.AnyArg(), sqlmock.AnyArg(), nil, dueAt, 1, nil).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"valid_in_project": {
+			task:        model.Task{Name: "Task 1", ProjectID: &projectID},
+			shouldError: false,
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				mock.ExpectQuery("SELECT (.+) FROM projects WHERE id = \\?").
+					WithArgs(projectID).
+					WillReturnRows(projectRows(mock, model.Project{ID: projectID, Name: "Project 1"}))
+				expectLastPosition(mock, nil)
+				return mock.ExpectExec("INSERT INTO tasks").
+					WithArgs(CUID{}, "Task 1", "", false, model.StatusTodo, "V", model.PriorityNone, projectID, nil, false, "", sqlmock.AnyArg(), sqlmock.AnyArg(), nil, nil, 1, nil).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"project_not_found": {
+			task:        model.Task{Name: "Task 1", ProjectID: &projectID},
+			shouldError: true,
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				mock.ExpectQuery("SELECT (.+) FROM projects WHERE id = \\?").
+					WithArgs(projectID).
+					WillReturnRows(projectRows(mock))
+				return nil
+			},
+		},
+		"valid_with_description": {
+			task:        model.Task{Name: "Task 1", Description: "Buy **milk**"},
 			shouldError: false,
 			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				expectLastPosition(mock, nil)
 				return mock.ExpectExec("INSERT INTO tasks").
-					WithArgs(CUID{}, "Task 1", false).
+					WithArgs(CUID{}, "Task 1", "Buy **milk**", false, model.StatusTodo, "V", model.PriorityNone, nil, nil, false, "", sqlmock.AnyArg(), sqlmock.AnyArg(), nil, nil, 1, nil).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 		},
@@ -249,24 +623,31 @@ func TestCreate(t *testing.T) {
 			defer db.Close()
 
 			test.query(mock)
+			if !test.shouldError {
+				expectHistory(mock, CUID{}, model.HistoryCreated, sqlmock.AnyArg())
+			}
 
-			repo := NewTaskRepository(db)
-			task, err := repo.Create(test.name)
+			repo := NewTaskRepository(db, nil)
+			task, err := repo.Create(context.Background(), test.task, testActor)
 
 			if test.shouldError {
 				assert.Error(err)
 				assert.Empty(task)
 			} else {
 				assert.NoError(err)
-				assert.Equal(test.name, task.Name)
+				assert.Equal(test.task.Name, task.Name)
+				assert.Equal(test.task.DueAt, task.DueAt)
 				assert.NoError(cuid.IsCuid(task.ID))
 				assert.False(task.Completed)
+				assert.False(task.CreatedAt.IsZero())
+				assert.Equal(task.CreatedAt, task.UpdatedAt)
 			}
 
 			assert.NoError(mock.ExpectationsWereMet())
 		})
 	}
 }
+
 func TestUpdate(t *testing.T) {
 	tests := map[string]struct {
 		task        model.Task
@@ -292,17 +673,412 @@ func TestUpdate(t *testing.T) {
 				return nil
 			},
 		},
+		"invalid_priority": {
+			task: model.Task{
+				ID:       "cl09rb83d000009l13y5n5ur8",
+				Name:     "Task 1",
+				Priority: model.Priority(-1),
+			},
+			shouldError: true,
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				return nil
+			},
+		},
 		"valid": {
 			task: model.Task{
 				ID:        "cl09rb83d000009l13y5n5ur8",
 				Name:      "Task 1",
 				Completed: true,
+				Priority:  model.PriorityHigh,
+				Version:   3,
+			},
+			shouldError: false,
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				expectTaskRow(mock, model.Task{ID: "cl09rb83d000009l13y5n5ur8", Name: "Task 1", Status: model.StatusInProgress, Version: 3})
+				expectOpenBlockers(mock, "cl09rb83d000009l13y5n5ur8", 0)
+				mock.ExpectExec("UPDATE tasks").
+					WithArgs("Task 1", "", true, model.StatusDone, true, sqlmock.AnyArg(), model.PriorityHigh, nil, false, "", nil, nil, sqlmock.AnyArg(), "cl09rb83d000009l13y5n5ur8", 3).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectHistory(mock, "cl09rb83d000009l13y5n5ur8", model.HistoryUpdated, []byte(`[{"field":"completed","from":false,"to":true},{"field":"status","from":"in_progress","to":"done"},{"field":"priority","from":"none","to":"high"}]`))
+				return nil
+			},
+		},
+		"blocked": {
+			task: model.Task{
+				ID:        "cl09rb83d000009l13y5n5ur8",
+				Name:      "Task 1",
+				Completed: true,
+			},
+			shouldError: true,
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				expectTaskRow(mock, model.Task{ID: "cl09rb83d000009l13y5n5ur8", Name: "Task 1", Status: model.StatusTodo})
+				expectOpenBlockers(mock, "cl09rb83d000009l13y5n5ur8", 2)
+				return nil
+			},
+		},
+		"rename_completed_with_open_blockers": {
+			task: model.Task{
+				ID:        "cl09rb83d000009l13y5n5ur8",
+				Name:      "Task 1 Updated",
+				Completed: true,
+			},
+			shouldError: false,
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				// Blockers are only checked when completing a task, so a completed
+				// task can still be edited after one of them is reopened.
+				expectTaskRow(mock, model.Task{ID: "cl09rb83d000009l13y5n5ur8", Name: "Task 1", Completed: true, Status: model.StatusDone})
+				mock.ExpectExec("UPDATE tasks").
+					WithArgs("Task 1 Updated", "", true, model.StatusDone, true, sqlmock.AnyArg(), model.PriorityNone, nil, false, "", nil, nil, sqlmock.AnyArg(), "cl09rb83d000009l13y5n5ur8", 0).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectHistory(mock, "cl09rb83d000009l13y5n5ur8", model.HistoryUpdated, []byte(`[{"field":"name","from":"Task 1","to":"Task 1 Updated"}]`))
+				return nil
+			},
+		},
+		"disallowed_transition": {
+			task: model.Task{
+				ID:     "cl09rb83d000009l13y5n5ur8",
+				Name:   "Task 1",
+				Status: model.StatusReview,
+			},
+			shouldError: true,
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				expectTaskRow(mock, model.Task{ID: "cl09rb83d000009l13y5n5ur8", Name: "Task 1", Status: model.StatusTodo})
+				return nil
+			},
+		},
+		"keeps_status": {
+			task: model.Task{
+				ID:   "cl09rb83d000009l13y5n5ur8",
+				Name: "Task 1",
 			},
 			shouldError: false,
 			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				expectTaskRow(mock, model.Task{ID: "cl09rb83d000009l13y5n5ur8", Name: "Task 1", Status: model.StatusReview})
+				return mock.ExpectExec("UPDATE tasks").
+					WithArgs("Task 1", "", false, model.StatusReview, false, sqlmock.AnyArg(), model.PriorityNone, nil, false, "", nil, nil, sqlmock.AnyArg(), "cl09rb83d000009l13y5n5ur8", 0).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"outdated_version": {
+			task: model.Task{
+				ID:      "cl09rb83d000009l13y5n5ur8",
+				Name:    "Task 1",
+				Version: 1,
+			},
+			shouldError: true,
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				expectTaskRow(mock, model.Task{ID: "cl09rb83d000009l13y5n5ur8", Name: "Task 1", Status: model.StatusTodo, Version: 2})
+				return nil
+			},
+		},
+		"changed_concurrently": {
+			task: model.Task{
+				ID:   "cl09rb83d000009l13y5n5ur8",
+				Name: "Task 1",
+			},
+			shouldError: true,
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				expectTaskRow(mock, model.Task{ID: "cl09rb83d000009l13y5n5ur8", Name: "Task 1", Status: model.StatusTodo, Version: 2})
 				return mock.ExpectExec("UPDATE tasks").
-					WithArgs("Task 1", true, "cl09rb83d000009l13y5n5ur8").
+					WithArgs("Task 1", "", false, model.StatusTodo, false, sqlmock.AnyArg(), model.PriorityNone, nil, false, "", nil, nil, sqlmock.AnyArg(), "cl09rb83d000009l13y5n5ur8", 2).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		"not_found": {
+			task: model.Task{
+				ID:   "cl09rb83d000009l13y5n5ur8",
+				Name: "Task 1",
+			},
+			shouldError: true,
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				mock.ExpectQuery("SELECT (.+) FROM tasks WHERE id = \\? AND deleted_at IS NULL").
+					WithArgs("cl09rb83d000009l13y5n5ur8").
+					WillReturnRows(taskRows(mock))
+				return nil
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.query(mock)
+
+			repo := NewTaskRepository(db, nil)
+			err := repo.Update(context.Background(), test.task, testActor)
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateCustomWorkflow(t *testing.T) {
+	if err := model.SetWorkflow(model.Workflow{
+		model.StatusTodo: {"approved"},
+		"approved":       {model.StatusDone},
+		model.StatusDone: {},
+	}); err != nil {
+		t.Fatalf("Error setting workflow: %s", err)
+	}
+
+	defer model.SetWorkflow(model.DefaultWorkflow)
+
+	tests := map[string]struct {
+		status model.Status
+		err    string
+		sql    func(mock sqlmock.Sqlmock)
+	}{
+		"allowed_transition": {
+			status: "approved",
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTaskRow(mock, model.Task{ID: testTaskID, Name: "Task 1", Status: model.StatusTodo})
+				mock.ExpectExec("UPDATE tasks").
+					WithArgs("Task 1", "", false, model.Status("approved"), false, sqlmock.AnyArg(), model.PriorityNone, nil, false, "", nil, nil, sqlmock.AnyArg(), testTaskID, 0).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectHistory(mock, testTaskID, model.HistoryUpdated, []byte(`[{"field":"status","from":"todo","to":"approved"}]`))
+			},
+		},
+		"disallowed_transition": {
+			status: model.StatusDone,
+			err:    "Cannot move a task from todo to done.",
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTaskRow(mock, model.Task{ID: testTaskID, Name: "Task 1", Status: model.StatusTodo})
+			},
+		},
+		"unknown_status": {
+			status: model.StatusInProgress,
+			err:    "Invalid task status.",
+			sql:    func(mock sqlmock.Sqlmock) {},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, nil)
+			err := repo.Update(context.Background(), model.Task{ID: testTaskID, Name: "Task 1", Status: test.status}, testActor)
+
+			if test.err != "" {
+				assert.EqualError(err, test.err)
+			} else {
+				assert.NoError(err)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPatch(t *testing.T) {
+	completed := model.Task{ID: testTaskID, Name: "Task 1", Completed: true, Status: model.StatusDone, Priority: model.PriorityHigh, Version: 2}
+
+	tests := map[string]struct {
+		patch       model.TaskPatch
+		shouldError bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"keeps_other_fields": {
+			patch: model.TaskPatch{Fields: []string{"name"}, Task: model.Task{Name: "Task 1 Updated"}},
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTaskRow(mock, completed)
+				expectTaskRow(mock, completed)
+				mock.ExpectExec("UPDATE tasks SET name = \\?").
+					WithArgs("Task 1 Updated", "", true, model.StatusDone, true, sqlmock.AnyArg(), model.PriorityHigh, nil, false, "", nil, nil, sqlmock.AnyArg(), testTaskID, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectHistory(mock, testTaskID, model.HistoryUpdated, []byte(`[{"field":"name","from":"Task 1","to":"Task 1 Updated"}]`))
+			},
+		},
+		"outdated_version": {
+			patch:       model.TaskPatch{Fields: []string{"name"}, Task: model.Task{Name: "Task 1 Updated", Version: 1}},
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTaskRow(mock, completed)
+				expectTaskRow(mock, completed)
+			},
+		},
+		"invalid_field": {
+			patch:       model.TaskPatch{Fields: []string{"position"}, Task: model.Task{Position: "V"}},
+			shouldError: true,
+			sql:         func(mock sqlmock.Sqlmock) {},
+		},
+		"not_found": {
+			patch:       model.TaskPatch{Fields: []string{"name"}, Task: model.Task{Name: "Task 1 Updated"}},
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM tasks WHERE id = \\? AND deleted_at IS NULL").
+					WithArgs(testTaskID).
+					WillReturnRows(taskRows(mock))
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, nil)
+			err := repo.Patch(context.Background(), testTaskID, test.patch, testActor)
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tests := map[string]struct {
+		id          string
+		shouldError bool
+		query       func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec
+	}{
+		"invalid_id": {
+			id:          "",
+			shouldError: true,
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				return nil
+			},
+		},
+		"existing": {
+			id:          "cl09rb83d000009l13y5n5ur8",
+			shouldError: false,
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				mock.ExpectExec("UPDATE tasks SET deleted_at").
+					WithArgs(sqlmock.AnyArg(), "cl09rb83d000009l13y5n5ur8").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectHistory(mock, "cl09rb83d000009l13y5n5ur8", model.HistoryDeleted, []byte(`[]`))
+				return nil
+			},
+		},
+		"non_existing": {
+			id:          "cl09rb83d000009l13y5n5ur8",
+			shouldError: true,
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				return mock.ExpectExec("UPDATE tasks SET deleted_at").
+					WithArgs(sqlmock.AnyArg(), "cl09rb83d000009l13y5n5ur8").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.query(mock)
+
+			repo := NewTaskRepository(db, nil)
+			err := repo.Delete(context.Background(), test.id, testActor)
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestListTrash(t *testing.T) {
+	deletedAt := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		expected []model.Task
+		query    func(mock sqlmock.Sqlmock) *sqlmock.ExpectedQuery
+	}{
+		"empty": {
+			expected: []model.Task{},
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedQuery {
+				return mock.ExpectQuery("SELECT (.+) FROM tasks WHERE deleted_at IS NOT NULL").
+					WillReturnRows(mock.NewRows(nil))
+			},
+		},
+		"one": {
+			expected: []model.Task{
+				{ID: "1", Name: "Task 1", Completed: false, DeletedAt: &deletedAt},
+			},
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedQuery {
+				return mock.ExpectQuery("SELECT (.+) FROM tasks WHERE deleted_at IS NOT NULL").
+					WillReturnRows(
+						taskRows(mock,
+							model.Task{ID: "1", Name: "Task 1", Completed: false, DeletedAt: &deletedAt},
+						),
+					)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.query(mock)
+
+			repo := NewTaskRepository(db, nil)
+			tasks, err := repo.ListTrash(context.Background())
+
+			assert.NoError(err)
+			assert.Equal(test.expected, tasks)
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	tests := map[string]struct {
+		id          string
+		shouldError bool
+		query       func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec
+	}{
+		"invalid_id": {
+			id:          "",
+			shouldError: true,
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				return nil
+			},
+		},
+		"trashed": {
+			id:          "cl09rb83d000009l13y5n5ur8",
+			shouldError: false,
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				mock.ExpectExec("UPDATE tasks SET deleted_at = NULL").
+					WithArgs("cl09rb83d000009l13y5n5ur8").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectHistory(mock, "cl09rb83d000009l13y5n5ur8", model.HistoryRestored, []byte(`[]`))
+				return nil
+			},
+		},
+		"not_trashed": {
+			id:          "cl09rb83d000009l13y5n5ur8",
+			shouldError: true,
+			query: func(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+				return mock.ExpectExec("UPDATE tasks SET deleted_at = NULL").
+					WithArgs("cl09rb83d000009l13y5n5ur8").
+					WillReturnResult(sqlmock.NewResult(0, 0))
 			},
 		},
 	}
@@ -314,13 +1090,118 @@ func TestUpdate(t *testing.T) {
 
 			test.query(mock)
 
-			repo := NewTaskRepository(db)
-			err := repo.Update(test.task)
+			repo := NewTaskRepository(db, nil)
+			err := repo.Restore(context.Background(), test.id, testActor)
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPurge(t *testing.T) {
+	before := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	expectPurgedAttachments := func(mock sqlmock.Sqlmock) {
+		mock.ExpectExec("DELETE FROM task_tags WHERE task_id IN").
+			WithArgs(before).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectQuery("SELECT id FROM attachments WHERE task_id IN").
+			WithArgs(before).
+			WillReturnRows(mock.NewRows([]string{"id"}).AddRow(testAttachmentID))
+		mock.ExpectExec("DELETE FROM attachments WHERE task_id IN").
+			WithArgs(before).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	tests := map[string]struct {
+		expected    int64
+		shouldError bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"valid": {
+			expected: 2,
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectPurgedAttachments(mock)
+				mock.ExpectExec("DELETE FROM task_history WHERE task_id IN").
+					WithArgs(before).
+					WillReturnResult(sqlmock.NewResult(0, 5))
+				mock.ExpectExec("DELETE FROM comments WHERE task_id IN").
+					WithArgs(before).
+					WillReturnResult(sqlmock.NewResult(0, 4))
+				mock.ExpectExec("DELETE FROM task_dependencies WHERE task_id IN").
+					WithArgs(before, before).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE tasks SET parent_id = NULL WHERE parent_id IN").
+					WithArgs(before).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at").
+					WithArgs(before).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+		},
+		"rolls_back_on_failure": {
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectPurgedAttachments(mock)
+				mock.ExpectExec("DELETE FROM task_history WHERE task_id IN").
+					WithArgs(before).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+		},
+		"failed_commit": {
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				expectPurgedAttachments(mock)
+				mock.ExpectExec("DELETE FROM task_history WHERE task_id IN").
+					WithArgs(before).
+					WillReturnResult(sqlmock.NewResult(0, 5))
+				mock.ExpectExec("DELETE FROM comments WHERE task_id IN").
+					WithArgs(before).
+					WillReturnResult(sqlmock.NewResult(0, 4))
+				mock.ExpectExec("DELETE FROM task_dependencies WHERE task_id IN").
+					WithArgs(before, before).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE tasks SET parent_id = NULL WHERE parent_id IN").
+					WithArgs(before).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at").
+					WithArgs(before).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit().WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			blobs := memoryStore{testAttachmentID: []byte("hello")}
+			repo := NewTaskRepository(db, blobs)
+			purged, err := repo.Purge(context.Background(), before)
 
 			if test.shouldError {
+				// The content of the attachments is kept when they are not purged.
 				assert.Error(err)
+				assert.Len(blobs, 1)
 			} else {
 				assert.NoError(err)
+				assert.Equal(test.expected, purged)
+				assert.Empty(blobs)
 			}
 
 			assert.NoError(mock.ExpectationsWereMet())
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+// MySQL error returned when a row with a duplicate key is inserted.
+const errDuplicateEntry = 1062
+
+func scanTags(rows *sql.Rows) ([]model.Tag, error) {
+	defer rows.Close()
+
+	tags := []model.Tag{}
+	for rows.Next() {
+		var tag model.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name); err != nil {
+			return nil, fmt.Errorf("Failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to scan tag: %w", err)
+	}
+
+	return tags, nil
+}
+
+// Lists all tags ordered by name.
+func (r *TaskRepository) ListTags(ctx context.Context) ([]model.Tag, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, "SELECT id, name FROM tags ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query tags: %w", err)
+	}
+
+	return scanTags(rows)
+}
+
+// Lists the tags attached to the task with the given ID, ordered by name.
+func (r *TaskRepository) ListTaskTags(ctx context.Context, id string) ([]model.Tag, error) {
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, "SELECT tags.id, tags.name FROM tags JOIN task_tags ON task_tags.tag_id = tags.id WHERE task_tags.task_id = ? ORDER BY tags.name", id)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query task tags: %w", err)
+	}
+
+	return scanTags(rows)
+}
+
+// Attaches the tag with the given name to the task with the given ID and
+// returns it. The tag is created if it does not exist yet, and attaching a tag
+// the task already has does nothing.
+func (r *TaskRepository) AttachTag(ctx context.Context, id string, name string) (model.Tag, error) {
+	tag, err := model.NewTag(name)
+	if err != nil {
+		return model.Tag{}, err
+	}
+
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return model.Tag{}, err
+	}
+
+	err = r.conn(ctx).QueryRowContext(ctx, "SELECT id, name FROM tags WHERE name = ?", tag.Name).Scan(&tag.ID, &tag.Name)
+	if err == sql.ErrNoRows {
+		_, err = r.conn(ctx).ExecContext(ctx, "INSERT INTO tags (id, name) VALUES (?, ?)", tag.ID, tag.Name)
+	}
+	if err != nil {
+		return model.Tag{}, fmt.Errorf("Failed to get or create tag: %w", err)
+	}
+
+	if _, err := r.conn(ctx).ExecContext(ctx, "INSERT INTO task_tags (task_id, tag_id) VALUES (?, ?)", id, tag.ID); err != nil {
+		if mysqlErr, ok := err.(*mysql.MySQLError); !ok || mysqlErr.Number != errDuplicateEntry {
+			return model.Tag{}, fmt.Errorf("Failed to attach tag: %w", err)
+		}
+	}
+
+	return tag, nil
+}
+
+// Detaches the tag with the given name from the task with the given ID.
+func (r *TaskRepository) DetachTag(ctx context.Context, id string, name string) error {
+	name = model.NormalizeTagName(name)
+
+	if err := model.ValidateTagName(name); err != nil {
+		return err
+	}
+
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, "DELETE FROM task_tags WHERE task_id = ? AND tag_id IN (SELECT id FROM tags WHERE name = ?)", id, name)
+	if err != nil {
+		return fmt.Errorf("Failed to detach tag: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to detach tag: %w", err)
+	}
+
+	if affected == 0 {
+		return errors.NewExternalError("Tag not found on task.")
+	}
+
+	return nil
+}
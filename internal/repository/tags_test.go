@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+const testTaskID = "cl09rb83d000009l13y5n5ur8"
+
+func expectTask(mock sqlmock.Sqlmock, found bool) {
+	rows := taskRows(mock)
+	if found {
+		rows = taskRows(mock, model.Task{ID: testTaskID, Name: "Task 1"})
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE id = \\? AND deleted_at IS NULL").
+		WithArgs(testTaskID).
+		WillReturnRows(rows)
+}
+
+func TestListTags(t *testing.T) {
+	assert, db, mock := beforeAll(t)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id, name FROM tags ORDER BY name").
+		WillReturnRows(
+			mock.NewRows([]string{"id", "name"}).
+				AddRow("1", "backend").
+				AddRow("2", "urgent"),
+		)
+
+	repo := NewTaskRepository(db, nil)
+	tags, err := repo.ListTags(context.Background())
+
+	assert.NoError(err)
+	assert.Equal([]model.Tag{{ID: "1", Name: "backend"}, {ID: "2", Name: "urgent"}}, tags)
+
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestListTaskTags(t *testing.T) {
+	tests := map[string]struct {
+		expected    []model.Tag
+		shouldError bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"tagged": {
+			expected: []model.Tag{{ID: "1", Name: "backend"}},
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				mock.ExpectQuery("SELECT tags.id, tags.name FROM tags JOIN task_tags (.+) WHERE task_tags.task_id = \\?").
+					WithArgs(testTaskID).
+					WillReturnRows(mock.NewRows([]string{"id", "name"}).AddRow("1", "backend"))
+			},
+		},
+		"task_not_found": {
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, false)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, nil)
+			tags, err := repo.ListTaskTags(context.Background(), testTaskID)
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				assert.Equal(test.expected, tags)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAttachTag(t *testing.T) {
+	tests := map[string]struct {
+		name        string
+		expected    model.Tag
+		shouldError bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"invalid_name": {
+			name:        "needs review",
+			shouldError: true,
+			sql:         func(mock sqlmock.Sqlmock) {},
+		},
+		"task_not_found": {
+			name:        "backend",
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, false)
+			},
+		},
+		"existing_tag": {
+			name:     "Backend",
+			expected: model.Tag{ID: "1", Name: "backend"},
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				mock.ExpectQuery("SELECT id, name FROM tags WHERE name = \\?").
+					WithArgs("backend").
+					WillReturnRows(mock.NewRows([]string{"id", "name"}).AddRow("1", "backend"))
+				mock.ExpectExec("INSERT INTO task_tags").
+					WithArgs(testTaskID, "1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"new_tag": {
+			name: "urgent",
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				mock.ExpectQuery("SELECT id, name FROM tags WHERE name = \\?").
+					WithArgs("urgent").
+					WillReturnRows(mock.NewRows([]string{"id", "name"}))
+				mock.ExpectExec("INSERT INTO tags").
+					WithArgs(CUID{}, "urgent").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO task_tags").
+					WithArgs(testTaskID, CUID{}).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		"already_attached": {
+			name:     "backend",
+			expected: model.Tag{ID: "1", Name: "backend"},
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				mock.ExpectQuery("SELECT id, name FROM tags WHERE name = \\?").
+					WithArgs("backend").
+					WillReturnRows(mock.NewRows([]string{"id", "name"}).AddRow("1", "backend"))
+				mock.ExpectExec("INSERT INTO task_tags").
+					WithArgs(testTaskID, "1").
+					WillReturnError(&mysql.MySQLError{Number: errDuplicateEntry})
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, nil)
+			tag, err := repo.AttachTag(context.Background(), testTaskID, test.name)
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				assert.Equal(model.NormalizeTagName(test.name), tag.Name)
+				if test.expected.ID != "" {
+					assert.Equal(test.expected, tag)
+				}
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDetachTag(t *testing.T) {
+	tests := map[string]struct {
+		shouldError bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"attached": {
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				mock.ExpectExec("DELETE FROM task_tags WHERE task_id = \\? AND tag_id IN").
+					WithArgs(testTaskID, "backend").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		"not_attached": {
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, true)
+				mock.ExpectExec("DELETE FROM task_tags WHERE task_id = \\? AND tag_id IN").
+					WithArgs(testTaskID, "backend").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		"task_not_found": {
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTask(mock, false)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, nil)
+			err := repo.DetachTag(context.Background(), testTaskID, "Backend")
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
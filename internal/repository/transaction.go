@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// The methods shared by *sql.DB and *sql.Tx used by the repository.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txKey struct{}
+
+// A transaction carried by a context, along with the side effects outside the
+// database, such as on attachment content, that wait for it to end.
+type transaction struct {
+	tx         *sql.Tx
+	onCommit   []func() error
+	onRollback []func()
+}
+
+func (t *transaction) rolledBack() {
+	for _, fn := range t.onRollback {
+		fn()
+	}
+}
+
+// Runs every function waiting for the commit, returning the first error.
+func (t *transaction) committed() error {
+	var first error
+	for _, fn := range t.onCommit {
+		if err := fn(); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
+
+// Returns the transaction carried by the given context, or the database when
+// there is none.
+func (r *TaskRepository) conn(ctx context.Context) querier {
+	if t, ok := ctx.Value(txKey{}).(*transaction); ok {
+		return t.tx
+	}
+
+	return r.db
+}
+
+// Runs the given function once the transaction carried by the context is
+// committed, or right away when there is none. Its error is returned by
+// Transaction, the changes to the database being kept.
+func (r *TaskRepository) afterCommit(ctx context.Context, fn func() error) error {
+	if t, ok := ctx.Value(txKey{}).(*transaction); ok {
+		t.onCommit = append(t.onCommit, fn)
+		return nil
+	}
+
+	return fn()
+}
+
+// Runs the given function if the transaction carried by the context is rolled
+// back. Without a transaction there is nothing to roll back.
+func (r *TaskRepository) afterRollback(ctx context.Context, fn func()) {
+	if t, ok := ctx.Value(txKey{}).(*transaction); ok {
+		t.onRollback = append(t.onRollback, fn)
+	}
+}
+
+// Runs the given function in a transaction. Repository calls made with the
+// context passed to it are part of the transaction, which is committed when the
+// function succeeds and rolled back when it returns an error or panics. When
+// the context already carries a transaction the function simply joins it.
+func (r *TaskRepository) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*transaction); ok {
+		return fn(ctx)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to begin transaction: %w", err)
+	}
+
+	t := &transaction{tx: tx}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			t.rolledBack()
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, t)); err != nil {
+		tx.Rollback()
+		t.rolledBack()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.rolledBack()
+		return fmt.Errorf("Failed to commit transaction: %w", err)
+	}
+
+	return t.committed()
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+func TestTransaction(t *testing.T) {
+	tests := map[string]struct {
+		fn          func(repo *TaskRepository, ctx context.Context) error
+		shouldError bool
+		shouldPanic bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"commits": {
+			fn: func(repo *TaskRepository, ctx context.Context) error {
+				return repo.Delete(ctx, testTaskID, testActor)
+			},
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE tasks SET deleted_at").
+					WithArgs(sqlmock.AnyArg(), testTaskID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectHistory(mock, testTaskID, model.HistoryDeleted, []byte(`[]`))
+				mock.ExpectCommit()
+			},
+		},
+		"rolls_back_on_error": {
+			fn: func(repo *TaskRepository, ctx context.Context) error {
+				if err := repo.Delete(ctx, testTaskID, testActor); err != nil {
+					return err
+				}
+
+				return repo.Restore(ctx, testSubtaskID, testActor)
+			},
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE tasks SET deleted_at").
+					WithArgs(sqlmock.AnyArg(), testTaskID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectHistory(mock, testTaskID, model.HistoryDeleted, []byte(`[]`))
+				mock.ExpectExec("UPDATE tasks SET deleted_at = NULL").
+					WithArgs(testSubtaskID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+		},
+		"rolls_back_on_panic": {
+			fn: func(repo *TaskRepository, ctx context.Context) error {
+				panic("boom")
+			},
+			shouldPanic: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+		},
+		"joins_outer_transaction": {
+			fn: func(repo *TaskRepository, ctx context.Context) error {
+				return repo.Transaction(ctx, func(ctx context.Context) error {
+					return errors.New("failed")
+				})
+			},
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, nil)
+			run := func() error {
+				return repo.Transaction(context.Background(), func(ctx context.Context) error {
+					return test.fn(repo, ctx)
+				})
+			}
+
+			if test.shouldPanic {
+				assert.Panics(func() { run() })
+			} else if err := run(); test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}
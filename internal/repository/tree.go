@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/mtbuzato/go-challenge/internal/errors"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+// Gets the task with the given ID along with all of its subtasks that are not
+// in the trash.
+func (r *TaskRepository) GetTree(ctx context.Context, id string) (model.TaskTree, error) {
+	root, err := r.GetByID(ctx, id)
+	if err != nil {
+		return model.TaskTree{}, err
+	}
+
+	descendants := []model.Task{}
+	seen := map[string]bool{root.ID: true}
+
+	for parentIDs := []string{root.ID}; len(parentIDs) > 0; {
+		placeholders := make([]string, len(parentIDs))
+		args := make([]interface{}, len(parentIDs))
+		for i, parentID := range parentIDs {
+			placeholders[i] = "?"
+			args[i] = parentID
+		}
+
+		rows, err := r.conn(ctx).QueryContext(ctx, "SELECT "+taskColumns+" FROM tasks WHERE parent_id IN ("+strings.Join(placeholders, ", ")+") AND deleted_at IS NULL ORDER BY id", args...)
+		if err != nil {
+			return model.TaskTree{}, fmt.Errorf("Failed to query subtasks: %w", err)
+		}
+
+		children, err := scanTasks(rows)
+		if err != nil {
+			return model.TaskTree{}, err
+		}
+
+		parentIDs = nil
+		for _, child := range children {
+			if !seen[child.ID] {
+				seen[child.ID] = true
+				descendants = append(descendants, child)
+				parentIDs = append(parentIDs, child.ID)
+			}
+		}
+	}
+
+	return model.NewTaskTree(root, descendants), nil
+}
+
+// Checks that the task with the given ID can be placed under the given
+// parent, which must exist and must not be the task or one of its subtasks.
+func (r *TaskRepository) validateParent(ctx context.Context, id string, parentID string) error {
+	if _, err := r.GetByID(ctx, parentID); err != nil {
+		if errors.IsExternal(err) {
+			return errors.NewExternalError("Parent task not found.")
+		}
+
+		return err
+	}
+
+	ancestorIDs := []string{}
+	seen := map[string]bool{}
+
+	for current := parentID; current != "" && !seen[current]; {
+		seen[current] = true
+		ancestorIDs = append(ancestorIDs, current)
+
+		var next sql.NullString
+		err := r.conn(ctx).QueryRowContext(ctx, "SELECT parent_id FROM tasks WHERE id = ?", current).Scan(&next)
+		if err == sql.ErrNoRows {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to get parent task: %w", err)
+		}
+
+		current = next.String
+	}
+
+	return model.ValidateHierarchy(id, ancestorIDs)
+}
+
+// Completes the task with the given ID if it auto-completes, all of its
+// subtasks are completed, it is not blocked and its workflow lets it be done,
+// then does the same for its own parent.
+func (r *TaskRepository) completeParents(ctx context.Context, id string, actor string) error {
+	for id != "" {
+		parent, err := r.GetByID(ctx, id)
+		if err != nil {
+			if errors.IsExternal(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		if !parent.AutoComplete || parent.Completed || model.ValidateTransition(parent.Status, model.StatusDone) != nil {
+			return nil
+		}
+
+		var pending int
+		if err := r.conn(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks WHERE parent_id = ? AND completed = ? AND deleted_at IS NULL", id, false).Scan(&pending); err != nil {
+			return fmt.Errorf("Failed to count pending subtasks: %w", err)
+		}
+
+		if pending > 0 {
+			return nil
+		}
+
+		blockers, err := r.countOpenBlockers(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if blockers > 0 {
+			return nil
+		}
+
+		now := model.Now()
+		if _, err := r.conn(ctx).ExecContext(ctx, "UPDATE tasks SET completed = ?, status = ?, completed_at = ?, updated_at = ?, version = version + 1 WHERE id = ?", true, model.StatusDone, now, now, id); err != nil {
+			return fmt.Errorf("Failed to complete parent task: %w", err)
+		}
+
+		completed := parent
+		completed.Completed = true
+		completed.Status = model.StatusDone
+
+		if err := r.recordHistory(ctx, parent.ID, model.HistoryUpdated, actor, model.DiffTasks(parent, completed)); err != nil {
+			return err
+		}
+
+		id = ""
+		if parent.ParentID != nil {
+			id = *parent.ParentID
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mtbuzato/go-challenge/internal/model"
+)
+
+const (
+	testSubtaskID    = "cl09rb83d000009l13y5n5ur1"
+	testSubsubtaskID = "cl09rb83d000009l13y5n5ur2"
+)
+
+func expectTaskRow(mock sqlmock.Sqlmock, task model.Task) {
+	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE id = \\? AND deleted_at IS NULL").
+		WithArgs(task.ID).
+		WillReturnRows(taskRows(mock, task))
+}
+
+func expectParentID(mock sqlmock.Sqlmock, id string, parentID interface{}) {
+	mock.ExpectQuery("SELECT parent_id FROM tasks WHERE id = \\?").
+		WithArgs(id).
+		WillReturnRows(mock.NewRows([]string{"parent_id"}).AddRow(parentID))
+}
+
+func TestGetTree(t *testing.T) {
+	rootID := testTaskID
+	subtaskID := testSubtaskID
+
+	root := model.Task{ID: rootID, Name: "Task 1"}
+	subtask := model.Task{ID: subtaskID, Name: "Task 2", ParentID: &rootID}
+	subsubtask := model.Task{ID: testSubsubtaskID, Name: "Task 3", ParentID: &subtaskID}
+
+	assert, db, mock := beforeAll(t)
+	defer db.Close()
+
+	expectTaskRow(mock, root)
+	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE parent_id IN \\(\\?\\) AND deleted_at IS NULL").
+		WithArgs(rootID).
+		WillReturnRows(taskRows(mock, subtask))
+	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE parent_id IN \\(\\?\\) AND deleted_at IS NULL").
+		WithArgs(subtaskID).
+		WillReturnRows(taskRows(mock, subsubtask))
+	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE parent_id IN \\(\\?\\) AND deleted_at IS NULL").
+		WithArgs(testSubsubtaskID).
+		WillReturnRows(taskRows(mock))
+
+	repo := NewTaskRepository(db, nil)
+	tree, err := repo.GetTree(context.Background(), rootID)
+
+	assert.NoError(err)
+	assert.Equal(model.TaskTree{
+		Task: root,
+		Subtasks: []model.TaskTree{
+			{
+				Task: subtask,
+				Subtasks: []model.TaskTree{
+					{Task: subsubtask, Subtasks: []model.TaskTree{}},
+				},
+			},
+		},
+	}, tree)
+
+	assert.NoError(mock.ExpectationsWereMet())
+}
+
+func TestUpdateSubtask(t *testing.T) {
+	rootID := testTaskID
+	subtaskID := testSubtaskID
+
+	tests := map[string]struct {
+		task        model.Task
+		shouldError bool
+		sql         func(mock sqlmock.Sqlmock)
+	}{
+		"cycle": {
+			task:        model.Task{ID: rootID, Name: "Task 1", ParentID: &subtaskID},
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTaskRow(mock, model.Task{ID: rootID, Name: "Task 1", Status: model.StatusTodo})
+				expectTaskRow(mock, model.Task{ID: subtaskID, Name: "Task 2", ParentID: &rootID})
+				expectParentID(mock, subtaskID, rootID)
+				expectParentID(mock, rootID, nil)
+			},
+		},
+		"parent_not_found": {
+			task:        model.Task{ID: subtaskID, Name: "Task 2", ParentID: &rootID},
+			shouldError: true,
+			sql: func(mock sqlmock.Sqlmock) {
+				expectTaskRow(mock, model.Task{ID: subtaskID, Name: "Task 2", Status: model.StatusTodo})
+				mock.ExpectQuery("SELECT (.+) FROM tasks WHERE id = \\? AND deleted_at IS NULL").
+					WithArgs(rootID).
+					WillReturnRows(taskRows(mock))
+			},
+		},
+		"completes_parent": {
+			task: model.Task{ID: subtaskID, Name: "Task 2", Completed: true, ParentID: &rootID},
+			sql: func(mock sqlmock.Sqlmock) {
+				parent := model.Task{ID: rootID, Name: "Task 1", Status: model.StatusTodo, AutoComplete: true}
+
+				expectTaskRow(mock, model.Task{ID: subtaskID, Name: "Task 2", Status: model.StatusTodo, ParentID: &rootID})
+				expectTaskRow(mock, parent)
+				expectParentID(mock, rootID, nil)
+				expectOpenBlockers(mock, subtaskID, 0)
+				mock.ExpectExec("UPDATE tasks SET name = \\?").
+					WithArgs("Task 2", "", true, model.StatusDone, true, sqlmock.AnyArg(), model.PriorityNone, rootID, false, "", nil, nil, sqlmock.AnyArg(), subtaskID, 0).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectHistory(mock, subtaskID, model.HistoryUpdated, sqlmock.AnyArg())
+				expectTaskRow(mock, parent)
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM tasks WHERE parent_id = \\? AND completed = \\?").
+					WithArgs(rootID, false).
+					WillReturnRows(mock.NewRows([]string{"count"}).AddRow(0))
+				expectOpenBlockers(mock, rootID, 0)
+				mock.ExpectExec("UPDATE tasks SET completed = \\?, status = \\?, completed_at = \\?, updated_at = \\?, version = version \\+ 1 WHERE id = \\?").
+					WithArgs(true, model.StatusDone, sqlmock.AnyArg(), sqlmock.AnyArg(), rootID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectHistory(mock, rootID, model.HistoryUpdated, []byte(`[{"field":"completed","from":false,"to":true},{"field":"status","from":"todo","to":"done"}]`))
+			},
+		},
+		"parent_is_blocked": {
+			task: model.Task{ID: subtaskID, Name: "Task 2", Completed: true, ParentID: &rootID},
+			sql: func(mock sqlmock.Sqlmock) {
+				parent := model.Task{ID: rootID, Name: "Task 1", Status: model.StatusTodo, AutoComplete: true}
+
+				expectTaskRow(mock, model.Task{ID: subtaskID, Name: "Task 2", Status: model.StatusTodo, ParentID: &rootID})
+				expectTaskRow(mock, parent)
+				expectParentID(mock, rootID, nil)
+				expectOpenBlockers(mock, subtaskID, 0)
+				mock.ExpectExec("UPDATE tasks SET name = \\?").
+					WithArgs("Task 2", "", true, model.StatusDone, true, sqlmock.AnyArg(), model.PriorityNone, rootID, false, "", nil, nil, sqlmock.AnyArg(), subtaskID, 0).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectHistory(mock, subtaskID, model.HistoryUpdated, sqlmock.AnyArg())
+				expectTaskRow(mock, parent)
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM tasks WHERE parent_id = \\? AND completed = \\?").
+					WithArgs(rootID, false).
+					WillReturnRows(mock.NewRows([]string{"count"}).AddRow(0))
+				expectOpenBlockers(mock, rootID, 1)
+			},
+		},
+		"parent_has_pending_subtasks": {
+			task: model.Task{ID: subtaskID, Name: "Task 2", Completed: true, ParentID: &rootID},
+			sql: func(mock sqlmock.Sqlmock) {
+				parent := model.Task{ID: rootID, Name: "Task 1", Status: model.StatusTodo, AutoComplete: true}
+
+				expectTaskRow(mock, model.Task{ID: subtaskID, Name: "Task 2", Status: model.StatusTodo, ParentID: &rootID})
+				expectTaskRow(mock, parent)
+				expectParentID(mock, rootID, nil)
+				expectOpenBlockers(mock, subtaskID, 0)
+				mock.ExpectExec("UPDATE tasks SET name = \\?").
+					WithArgs("Task 2", "", true, model.StatusDone, true, sqlmock.AnyArg(), model.PriorityNone, rootID, false, "", nil, nil, sqlmock.AnyArg(), subtaskID, 0).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectHistory(mock, subtaskID, model.HistoryUpdated, sqlmock.AnyArg())
+				expectTaskRow(mock, parent)
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM tasks WHERE parent_id = \\? AND completed = \\?").
+					WithArgs(rootID, false).
+					WillReturnRows(mock.NewRows([]string{"count"}).AddRow(1))
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert, db, mock := beforeAll(t)
+			defer db.Close()
+
+			test.sql(mock)
+
+			repo := NewTaskRepository(db, nil)
+			err := repo.Update(context.Background(), test.task, testActor)
+
+			if test.shouldError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			assert.NoError(mock.ExpectationsWereMet())
+		})
+	}
+}